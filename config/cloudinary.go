@@ -1,14 +1,9 @@
 package config
 
 import (
-	"meals-app/helper"
-	"os"
-
 	"github.com/cloudinary/cloudinary-go/v2"
 )
 
-func NewCloudinary() *cloudinary.Cloudinary {
-	cld, err := cloudinary.NewFromParams(os.Getenv("CLOUD_NAME"), os.Getenv("CLOUDINARY_API_KEY"), os.Getenv("CLOUDINARY_API_SECRET"))
-	helper.PanicError(err)
-	return cld
+func NewCloudinary(cfg CloudinaryConfig) (*cloudinary.Cloudinary, error) {
+	return cloudinary.NewFromParams(cfg.CloudName, cfg.APIKey, cfg.APISecret)
 }
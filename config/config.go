@@ -0,0 +1,300 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/creasty/defaults"
+	"github.com/go-playground/validator/v10"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	App        AppConfig        `yaml:"app"`
+	Database   DatabaseConfig   `yaml:"database"`
+	JWT        JWTConfig        `yaml:"jwt"`
+	Storage    StorageConfig    `yaml:"storage"`
+	Cloudinary CloudinaryConfig `yaml:"cloudinary"`
+	Mail       MailConfig       `yaml:"mail"`
+	Account    AccountConfig    `yaml:"account"`
+	Password   PasswordConfig   `yaml:"password"`
+	Login      LoginConfig      `yaml:"login"`
+	OIDC       OIDCConfig       `yaml:"oidc"`
+	TwoFactor  TwoFactorConfig  `yaml:"two_factor"`
+}
+
+// AppConfig.ProxyHeader names the header carrying the client IP address
+// when the app runs behind a load balancer. It is only read on requests
+// from TrustedProxies (addresses or CIDR ranges), and should be one the
+// proxy overwrites, such as X-Real-IP, since clients can send it too.
+type AppConfig struct {
+	Port            int           `yaml:"port" default:"3000" validate:"min=1,max=65535"`
+	BaseURL         string        `yaml:"base_url" default:"http://localhost:3000" validate:"required,url"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" default:"10s" validate:"gt=0"`
+	ProxyHeader     string        `yaml:"proxy_header"`
+	TrustedProxies  []string      `yaml:"trusted_proxies" validate:"required_with=ProxyHeader,dive,ip|cidr"`
+}
+
+type DatabaseConfig struct {
+	Driver string `yaml:"driver" default:"mysql" validate:"oneof=mysql postgres sqlite"`
+	URL    string `yaml:"url" validate:"required"`
+}
+
+// JWTConfig selects how access tokens are signed. HS256 uses Secret. RS256
+// and EdDSA use Keys: the key named by SigningKeyID (or the first key with
+// a private key file) signs new tokens and every key verifies them, so a
+// retired key can stay listed with only its public key during rotation.
+type JWTConfig struct {
+	Algorithm     string         `yaml:"algorithm" default:"HS256" validate:"oneof=HS256 RS256 EdDSA"`
+	Secret        string         `yaml:"secret" validate:"required_if=Algorithm HS256"`
+	SigningKeyID  string         `yaml:"signing_key_id"`
+	Keys          []JWTKeyConfig `yaml:"keys" validate:"required_unless=Algorithm HS256,dive"`
+	Expiry        time.Duration  `yaml:"expiry" default:"15m" validate:"gt=0"`
+	RefreshExpiry time.Duration  `yaml:"refresh_expiry" default:"720h" validate:"gt=0"`
+}
+
+type JWTKeyConfig struct {
+	ID             string `yaml:"id" validate:"required"`
+	PrivateKeyFile string `yaml:"private_key_file" validate:"required_without=PublicKeyFile"`
+	PublicKeyFile  string `yaml:"public_key_file"`
+}
+
+type StorageConfig struct {
+	Driver   string `yaml:"driver" default:"cloudinary" validate:"oneof=cloudinary local"`
+	LocalDir string `yaml:"local_dir" default:"./uploads"`
+}
+
+type CloudinaryConfig struct {
+	CloudName string `yaml:"cloud_name"`
+	APIKey    string `yaml:"api_key"`
+	APISecret string `yaml:"api_secret"`
+	Folder    string `yaml:"folder" default:"meals-app"`
+}
+
+type MailConfig struct {
+	Driver    string     `yaml:"driver" default:"outbox" validate:"oneof=smtp outbox"`
+	From      string     `yaml:"from" default:"Meals App <no-reply@localhost>" validate:"required"`
+	OutboxDir string     `yaml:"outbox_dir" default:"./outbox"`
+	SMTP      SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string        `yaml:"host"`
+	Port     int           `yaml:"port" default:"587" validate:"min=1,max=65535"`
+	Username string        `yaml:"username"`
+	Password string        `yaml:"password"`
+	Timeout  time.Duration `yaml:"timeout" default:"10s" validate:"gt=0"`
+}
+
+type PasswordConfig struct {
+	MinLength     int  `yaml:"min_length" default:"8" validate:"min=1,max=72"`
+	RequireUpper  bool `yaml:"require_upper" default:"true"`
+	RequireLower  bool `yaml:"require_lower" default:"true"`
+	RequireDigit  bool `yaml:"require_digit" default:"true"`
+	RequireSymbol bool `yaml:"require_symbol" default:"false"`
+}
+
+// LoginConfig controls brute-force protection. Every failed login delays
+// the next attempt for the same email and IP address by BaseDelay, doubling
+// each time up to LockoutDuration, and MaxAttempts (IPMaxAttempts for an IP
+// address) failures in a row lock it out for LockoutDuration.
+type LoginConfig struct {
+	MaxAttempts     int           `yaml:"max_attempts" default:"5" validate:"min=1"`
+	IPMaxAttempts   int           `yaml:"ip_max_attempts" default:"20" validate:"min=1"`
+	BaseDelay       time.Duration `yaml:"base_delay" default:"1s" validate:"gte=0"`
+	LockoutDuration time.Duration `yaml:"lockout_duration" default:"15m" validate:"gt=0"`
+}
+
+type OIDCConfig struct {
+	StateExpiry time.Duration        `yaml:"state_expiry" default:"10m" validate:"gt=0"`
+	Providers   []OIDCProviderConfig `yaml:"providers" validate:"dive"`
+}
+
+// OIDCProviderConfig registers an OpenID Connect provider. Its callback
+// URL is APP_BASE_URL/api/oidc/<name>/callback.
+type OIDCProviderConfig struct {
+	Name         string   `yaml:"name" validate:"required,alphanum"`
+	IssuerURL    string   `yaml:"issuer_url" validate:"required,url"`
+	ClientID     string   `yaml:"client_id" validate:"required"`
+	ClientSecret string   `yaml:"client_secret"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+// TwoFactorConfig controls TOTP two-factor authentication. Issuer is the
+// account name shown in authenticator apps.
+type TwoFactorConfig struct {
+	Issuer          string        `yaml:"issuer" default:"Meals App" validate:"required"`
+	ChallengeExpiry time.Duration `yaml:"challenge_expiry" default:"5m" validate:"gt=0"`
+	RecoveryCodes   int           `yaml:"recovery_codes" default:"10" validate:"min=1,max=50"`
+}
+
+// AccountConfig also controls account deletion: accounts are deleted
+// DeletionGracePeriod after the user asks, and DeletedRecipes decides
+// whether their recipes stay under an anonymous owner or go with them.
+// Users without a password they know confirm the deletion with a mailed
+// token valid for DeletionTokenExpiry.
+type AccountConfig struct {
+	VerificationExpiry  time.Duration `yaml:"verification_expiry" default:"24h" validate:"gt=0"`
+	PasswordResetExpiry time.Duration `yaml:"password_reset_expiry" default:"1h" validate:"gt=0"`
+	PasswordResetURL    string        `yaml:"password_reset_url" validate:"omitempty,url"`
+	DeletionGracePeriod time.Duration `yaml:"deletion_grace_period" default:"720h" validate:"gte=0"`
+	DeletionTokenExpiry time.Duration `yaml:"deletion_token_expiry" default:"1h" validate:"gt=0"`
+	DeletedRecipes      string        `yaml:"deleted_recipes" default:"anonymize" validate:"oneof=anonymize delete"`
+}
+
+// Load builds the configuration from defaults, then the YAML file named by
+// CONFIG_FILE (config.yaml when unset), then environment variables, which
+// may also come from a .env file. The result is validated before returning.
+func Load() (*Config, error) {
+	err := godotenv.Load(".env")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	err = defaults.Set(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	configFile := os.Getenv("CONFIG_FILE")
+	if configFile == "" {
+		configFile = "config.yaml"
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err == nil {
+		err = yaml.Unmarshal(content, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", configFile, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) || os.Getenv("CONFIG_FILE") != "" {
+		return nil, err
+	}
+
+	err = cfg.loadEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) loadEnv() error {
+	setString(&cfg.App.BaseURL, "APP_BASE_URL")
+	setString(&cfg.App.ProxyHeader, "PROXY_HEADER")
+	setList(&cfg.App.TrustedProxies, "TRUSTED_PROXIES")
+	setString(&cfg.Database.Driver, "DB_DRIVER")
+	setString(&cfg.Database.URL, "DB_URL")
+	setString(&cfg.JWT.Secret, "JWT_TOKEN_SECRET")
+	setString(&cfg.JWT.Algorithm, "JWT_ALGORITHM")
+	setString(&cfg.Storage.Driver, "IMAGE_STORAGE")
+	setString(&cfg.Storage.LocalDir, "LOCAL_STORAGE_DIR")
+	setString(&cfg.Cloudinary.CloudName, "CLOUD_NAME")
+	setString(&cfg.Cloudinary.APIKey, "CLOUDINARY_API_KEY")
+	setString(&cfg.Cloudinary.APISecret, "CLOUDINARY_API_SECRET")
+	setString(&cfg.Cloudinary.Folder, "CLOUDINARY_FOLDER")
+	setString(&cfg.Account.PasswordResetURL, "PASSWORD_RESET_URL")
+	setString(&cfg.Mail.Driver, "MAIL_DRIVER")
+	setString(&cfg.Mail.From, "MAIL_FROM")
+	setString(&cfg.Mail.OutboxDir, "MAIL_OUTBOX_DIR")
+	setString(&cfg.Mail.SMTP.Host, "SMTP_HOST")
+	setString(&cfg.Mail.SMTP.Username, "SMTP_USERNAME")
+	setString(&cfg.Mail.SMTP.Password, "SMTP_PASSWORD")
+	setString(&cfg.TwoFactor.Issuer, "TOTP_ISSUER")
+	setString(&cfg.Account.DeletedRecipes, "ACCOUNT_DELETED_RECIPES")
+
+	// A key file from the environment becomes the signing key; keys from
+	// config.yaml stay available for verification.
+	if file := os.Getenv("JWT_PRIVATE_KEY_FILE"); file != "" {
+		id := os.Getenv("JWT_KEY_ID")
+		if id == "" {
+			id = "default"
+		}
+		cfg.JWT.Keys = append([]JWTKeyConfig{{ID: id, PrivateKeyFile: file}}, cfg.JWT.Keys...)
+		cfg.JWT.SigningKeyID = id
+	}
+
+	return errors.Join(
+		setInt(&cfg.App.Port, "PORT"),
+		setInt(&cfg.Mail.SMTP.Port, "SMTP_PORT"),
+		setInt(&cfg.Password.MinLength, "PASSWORD_MIN_LENGTH"),
+		setInt(&cfg.Login.MaxAttempts, "LOGIN_MAX_ATTEMPTS"),
+		setInt(&cfg.Login.IPMaxAttempts, "LOGIN_IP_MAX_ATTEMPTS"),
+		setDuration(&cfg.App.ShutdownTimeout, "SHUTDOWN_TIMEOUT"),
+		setDuration(&cfg.JWT.Expiry, "JWT_EXPIRY"),
+		setDuration(&cfg.JWT.RefreshExpiry, "JWT_REFRESH_EXPIRY"),
+		setDuration(&cfg.Account.VerificationExpiry, "VERIFICATION_EXPIRY"),
+		setDuration(&cfg.Account.PasswordResetExpiry, "PASSWORD_RESET_EXPIRY"),
+		setDuration(&cfg.Login.BaseDelay, "LOGIN_BASE_DELAY"),
+		setDuration(&cfg.Login.LockoutDuration, "LOGIN_LOCKOUT_DURATION"),
+		setDuration(&cfg.TwoFactor.ChallengeExpiry, "TWO_FACTOR_CHALLENGE_EXPIRY"),
+		setDuration(&cfg.Account.DeletionGracePeriod, "ACCOUNT_DELETION_GRACE_PERIOD"),
+		setDuration(&cfg.Account.DeletionTokenExpiry, "ACCOUNT_DELETION_TOKEN_EXPIRY"),
+		setDuration(&cfg.Mail.SMTP.Timeout, "SMTP_TIMEOUT"),
+	)
+}
+
+func (cfg *Config) Validate() error {
+	err := validator.New().Struct(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if cfg.Storage.Driver == "cloudinary" && (cfg.Cloudinary.CloudName == "" || cfg.Cloudinary.APIKey == "" || cfg.Cloudinary.APISecret == "") {
+		return errors.New("invalid configuration: cloudinary storage requires CLOUD_NAME, CLOUDINARY_API_KEY and CLOUDINARY_API_SECRET")
+	}
+
+	if cfg.Mail.Driver == "smtp" && cfg.Mail.SMTP.Host == "" {
+		return errors.New("invalid configuration: smtp mail driver requires SMTP_HOST")
+	}
+
+	return nil
+}
+
+func setString(target *string, key string) {
+	if value := os.Getenv(key); value != "" {
+		*target = value
+	}
+}
+
+func setList(target *[]string, key string) {
+	if value := os.Getenv(key); value != "" {
+		*target = strings.Split(value, ",")
+		for i := range *target {
+			(*target)[i] = strings.TrimSpace((*target)[i])
+		}
+	}
+}
+
+func setInt(target *int, key string) error {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		*target = parsed
+	}
+	return nil
+}
+
+func setDuration(target *time.Duration, key string) error {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		*target = parsed
+	}
+	return nil
+}
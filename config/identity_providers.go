@@ -0,0 +1,20 @@
+package config
+
+import (
+	"meals-app/identity"
+	"strings"
+)
+
+func NewIdentityProviders(cfg *Config) map[string]identity.Provider {
+	providers := map[string]identity.Provider{}
+	for _, provider := range cfg.OIDC.Providers {
+		scopes := provider.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"email", "profile"}
+		}
+
+		redirectURL := strings.TrimSuffix(cfg.App.BaseURL, "/") + "/api/oidc/" + provider.Name + "/callback"
+		providers[provider.Name] = identity.NewOIDCProvider(provider.IssuerURL, provider.ClientID, provider.ClientSecret, redirectURL, scopes)
+	}
+	return providers
+}
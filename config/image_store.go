@@ -0,0 +1,18 @@
+package config
+
+import (
+	"meals-app/storage"
+)
+
+func NewImageStore(cfg *Config) (storage.ImageStore, error) {
+	switch cfg.Storage.Driver {
+	case "local":
+		return storage.NewLocalImageStore(cfg.Storage.LocalDir, "/uploads", cfg.App.BaseURL), nil
+	default:
+		cld, err := NewCloudinary(cfg.Cloudinary)
+		if err != nil {
+			return nil, err
+		}
+		return storage.NewCloudinaryImageStore(cld, cfg.Cloudinary.Folder), nil
+	}
+}
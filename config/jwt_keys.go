@@ -0,0 +1,53 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"meals-app/signing"
+	"os"
+)
+
+func NewKeySet(cfg JWTConfig) (*signing.KeySet, error) {
+	if cfg.Algorithm == "HS256" {
+		// Derive the kid from the secret so tokens signed with an old
+		// secret are told apart instead of failing signature checks.
+		sum := sha256.Sum256([]byte(cfg.Secret))
+		secret := []byte(cfg.Secret)
+		return signing.NewKeySet(cfg.Algorithm, "", []signing.Key{{
+			ID:         hex.EncodeToString(sum[:4]),
+			PrivateKey: secret,
+			PublicKey:  secret,
+		}})
+	}
+
+	var keys []signing.Key
+	for _, keyConfig := range cfg.Keys {
+		var key signing.Key
+		var err error
+		if keyConfig.PrivateKeyFile != "" {
+			key, err = loadKey(keyConfig.ID, keyConfig.PrivateKeyFile, cfg.Algorithm, signing.ParsePrivateKey)
+		} else {
+			key, err = loadKey(keyConfig.ID, keyConfig.PublicKeyFile, cfg.Algorithm, signing.ParsePublicKey)
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return signing.NewKeySet(cfg.Algorithm, cfg.SigningKeyID, keys)
+}
+
+func loadKey(id string, file string, algorithm string, parse func(string, string, []byte) (signing.Key, error)) (signing.Key, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return signing.Key{}, fmt.Errorf("JWT key %q: %w", id, err)
+	}
+
+	key, err := parse(id, algorithm, content)
+	if err != nil {
+		return signing.Key{}, fmt.Errorf("JWT key %q: %w", id, err)
+	}
+	return key, nil
+}
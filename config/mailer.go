@@ -0,0 +1,14 @@
+package config
+
+import (
+	"meals-app/mail"
+)
+
+func NewMailer(cfg MailConfig) mail.Mailer {
+	switch cfg.Driver {
+	case "smtp":
+		return mail.NewSMTPMailer(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.From, cfg.SMTP.Timeout)
+	default:
+		return mail.NewOutboxMailer(cfg.OutboxDir, cfg.From)
+	}
+}
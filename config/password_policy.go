@@ -0,0 +1,170 @@
+package config
+
+import (
+	_ "embed"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var commonPasswords = func() map[string]bool {
+	passwords := map[string]bool{}
+	for _, password := range strings.Fields(commonPasswordList) {
+		passwords[password] = true
+	}
+	return passwords
+}()
+
+type passwordClass struct {
+	en, id string
+	match  func(rune) bool
+}
+
+func isSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
+
+// requiredClasses lists the character classes the policy asks for.
+func (policy PasswordConfig) requiredClasses() []passwordClass {
+	var classes []passwordClass
+	if policy.RequireUpper {
+		classes = append(classes, passwordClass{"an uppercase letter", "huruf besar", unicode.IsUpper})
+	}
+	if policy.RequireLower {
+		classes = append(classes, passwordClass{"a lowercase letter", "huruf kecil", unicode.IsLower})
+	}
+	if policy.RequireDigit {
+		classes = append(classes, passwordClass{"a digit", "angka", unicode.IsDigit})
+	}
+	if policy.RequireSymbol {
+		classes = append(classes, passwordClass{"a symbol", "simbol", isSymbol})
+	}
+	return classes
+}
+
+// maxPasswordBytes is the most bcrypt hashes; it fails on longer input.
+const maxPasswordBytes = 72
+
+// registerPasswordPolicy adds the password_length, password_bytes,
+// password_classes, password_common and password_identity rules together
+// with their English and Indonesian messages.
+func registerPasswordPolicy(validate *validator.Validate, uni *ut.UniversalTranslator, policy PasswordConfig) error {
+	classes := policy.requiredClasses()
+
+	rules := map[string]validator.Func{
+		"password_length": func(fl validator.FieldLevel) bool {
+			return len([]rune(fl.Field().String())) >= policy.MinLength
+		},
+		"password_bytes": func(fl validator.FieldLevel) bool {
+			return len(fl.Field().String()) <= maxPasswordBytes
+		},
+		"password_classes": func(fl validator.FieldLevel) bool {
+			password := fl.Field().String()
+			for _, class := range classes {
+				if !strings.ContainsFunc(password, class.match) {
+					return false
+				}
+			}
+			return true
+		},
+		"password_common": func(fl validator.FieldLevel) bool {
+			return !commonPasswords[strings.ToLower(fl.Field().String())]
+		},
+		"password_identity": passwordIdentity,
+	}
+	for tag, rule := range rules {
+		err := validate.RegisterValidation(tag, rule)
+		if err != nil {
+			return err
+		}
+	}
+
+	var enClasses, idClasses []string
+	for _, class := range classes {
+		enClasses = append(enClasses, class.en)
+		idClasses = append(idClasses, class.id)
+	}
+	minLength := strconv.Itoa(policy.MinLength)
+	maxBytes := strconv.Itoa(maxPasswordBytes)
+
+	messages := map[string]map[string]string{
+		"en": {
+			"password_length":   "{0} must be at least " + minLength + " characters long",
+			"password_bytes":    "{0} must be at most " + maxBytes + " bytes long, accented letters and emoji take more than one",
+			"password_classes":  "{0} must contain " + joinList(enClasses, "and"),
+			"password_common":   "{0} is too common, choose one that is harder to guess",
+			"password_identity": "{0} must not contain your username or email",
+		},
+		"id": {
+			"password_length":   "{0} minimal " + minLength + " karakter",
+			"password_bytes":    "{0} maksimal " + maxBytes + " byte, huruf beraksen dan emoji memakai lebih dari satu",
+			"password_classes":  "{0} harus mengandung " + joinList(idClasses, "dan"),
+			"password_common":   "{0} terlalu umum, pilih yang lebih sulit ditebak",
+			"password_identity": "{0} tidak boleh mengandung username atau email Anda",
+		},
+	}
+	for locale, localeMessages := range messages {
+		trans, _ := uni.GetTranslator(locale)
+		for tag, message := range localeMessages {
+			err := validate.RegisterTranslation(tag, trans, registerMessage(tag, message), translateMessage(tag))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// passwordIdentity rejects passwords containing the Username or Email of
+// the struct being validated, or the local part of that email.
+func passwordIdentity(fl validator.FieldLevel) bool {
+	password := strings.ToLower(fl.Field().String())
+
+	var identities []string
+	parent := reflect.Indirect(fl.Parent())
+	for _, name := range []string{"Username", "Email"} {
+		field := parent.FieldByName(name)
+		if !field.IsValid() || field.Kind() != reflect.String {
+			continue
+		}
+
+		identity := strings.ToLower(field.String())
+		local, _, _ := strings.Cut(identity, "@")
+		identities = append(identities, identity, local)
+	}
+
+	for _, identity := range identities {
+		if len(identity) >= 3 && strings.Contains(password, identity) {
+			return false
+		}
+	}
+	return true
+}
+
+func registerMessage(tag string, message string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, message, true)
+	}
+}
+
+func translateMessage(tag string) validator.TranslationFunc {
+	return func(trans ut.Translator, fe validator.FieldError) string {
+		message, _ := trans.T(tag, fe.Field())
+		return message
+	}
+}
+
+func joinList(items []string, conjunction string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + conjunction + " " + items[len(items)-1]
+}
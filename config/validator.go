@@ -0,0 +1,51 @@
+package config
+
+import (
+	"meals-app/helper"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
+)
+
+// NewValidator returns a validator that reports fields by their json/form
+// tag names, along with a translator holding English and Indonesian
+// messages for every built-in rule and for the password policy rules.
+func NewValidator(policy PasswordConfig) (*validator.Validate, *ut.UniversalTranslator) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(fieldTagName)
+
+	enLocale := en.New()
+	uni := ut.New(enLocale, enLocale, id.New())
+
+	enTrans, _ := uni.GetTranslator("en")
+	err := enTranslations.RegisterDefaultTranslations(validate, enTrans)
+	helper.PanicError(err)
+
+	idTrans, _ := uni.GetTranslator("id")
+	err = idTranslations.RegisterDefaultTranslations(validate, idTrans)
+	helper.PanicError(err)
+
+	err = registerPasswordPolicy(validate, uni, policy)
+	helper.PanicError(err)
+
+	return validate, uni
+}
+
+func fieldTagName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form", "query"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return strings.TrimSuffix(name, "[]")
+		}
+	}
+	return field.Name
+}
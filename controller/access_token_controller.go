@@ -0,0 +1,9 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type AccessTokenController interface {
+	CreateAccessTokenCtrl(c *fiber.Ctx) error
+	GetAllAccessTokenCtrl(c *fiber.Ctx) error
+	DeleteAccessTokenCtrl(c *fiber.Ctx) error
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"meals-app/exception"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AccessTokenControllerImpl struct {
+	AccessTokenService service.AccessTokenService
+}
+
+func NewAccessTokenControllerImpl(accessTokenService service.AccessTokenService) AccessTokenController {
+	return &AccessTokenControllerImpl{
+		AccessTokenService: accessTokenService,
+	}
+}
+
+func (controller *AccessTokenControllerImpl) CreateAccessTokenCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	request := new(web.CreateAccessTokenReq)
+	err := c.BodyParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	response, err := controller.AccessTokenService.Create(c.Context(), user, *request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *AccessTokenControllerImpl) GetAllAccessTokenCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	responses, err := controller.AccessTokenService.FindAll(c.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   responses,
+	})
+}
+
+func (controller *AccessTokenControllerImpl) DeleteAccessTokenCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	tokenID, err := c.ParamsInt("id")
+	if err != nil {
+		return exception.NewBadInputError("id must be a number")
+	}
+
+	err = controller.AccessTokenService.Revoke(c.Context(), user, tokenID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   "access token revoked successfully",
+	})
+}
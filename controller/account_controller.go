@@ -0,0 +1,10 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type AccountController interface {
+	ExportCtrl(c *fiber.Ctx) error
+	RequestDeletionTokenCtrl(c *fiber.Ctx) error
+	DeleteAccountCtrl(c *fiber.Ctx) error
+	CancelDeletionCtrl(c *fiber.Ctx) error
+}
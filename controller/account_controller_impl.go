@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"meals-app/exception"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AccountControllerImpl struct {
+	AccountService service.AccountService
+}
+
+func NewAccountControllerImpl(accountService service.AccountService) AccountController {
+	return &AccountControllerImpl{
+		AccountService: accountService,
+	}
+}
+
+// ExportCtrl downloads the user's data as a ZIP archive of JSON files, or
+// as a single JSON document with ?format=json.
+func (controller *AccountControllerImpl) ExportCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	format := c.Query("format", "zip")
+	if format != "zip" && format != "json" {
+		return exception.NewBadInputError("format must be zip or json")
+	}
+
+	export, err := controller.AccountService.Export(c.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		c.Attachment("meals-app-export.json")
+		return c.JSON(export)
+	}
+
+	archive, err := exportArchive(export)
+	if err != nil {
+		return err
+	}
+
+	c.Attachment("meals-app-export.zip")
+	return c.Send(archive)
+}
+
+func (controller *AccountControllerImpl) RequestDeletionTokenCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	err := controller.AccountService.RequestDeletionToken(c.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   "account deletion token sent, check your email",
+	})
+}
+
+func (controller *AccountControllerImpl) DeleteAccountCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	request := new(web.DeleteAccountReq)
+	err := c.BodyParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	response, err := controller.AccountService.ScheduleDeletion(c.Context(), user, *request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *AccountControllerImpl) CancelDeletionCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	err := controller.AccountService.CancelDeletion(c.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   "account deletion cancelled",
+	})
+}
+
+func exportArchive(export web.AccountExport) ([]byte, error) {
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"profile.json", export.Profile},
+		{"recipes.json", export.Recipes},
+		{"favorites.json", export.Favorites},
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(file.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := archive.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
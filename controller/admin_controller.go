@@ -0,0 +1,13 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type AdminController interface {
+	GetAllUserCtrl(c *fiber.Ctx) error
+	GetUserByIDCtrl(c *fiber.Ctx) error
+	UpdateRoleCtrl(c *fiber.Ctx) error
+	SuspendUserCtrl(c *fiber.Ctx) error
+	UnsuspendUserCtrl(c *fiber.Ctx) error
+	ForcePasswordResetCtrl(c *fiber.Ctx) error
+	UnlockUserCtrl(c *fiber.Ctx) error
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"meals-app/exception"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminControllerImpl struct {
+	AdminService         service.AdminService
+	LoginThrottleService service.LoginThrottleService
+}
+
+func NewAdminControllerImpl(adminService service.AdminService, loginThrottleService service.LoginThrottleService) AdminController {
+	return &AdminControllerImpl{
+		AdminService:         adminService,
+		LoginThrottleService: loginThrottleService,
+	}
+}
+
+func (controller *AdminControllerImpl) GetAllUserCtrl(c *fiber.Ctx) error {
+	request := new(web.SearchUsersReq)
+	err := c.QueryParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	responses, meta, err := controller.AdminService.FindUsers(c.Context(), *request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   responses,
+		"meta":   meta,
+	})
+}
+
+func (controller *AdminControllerImpl) GetUserByIDCtrl(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return exception.NewBadInputError("id must be a number")
+	}
+
+	response, err := controller.AdminService.FindUserByID(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *AdminControllerImpl) UpdateRoleCtrl(c *fiber.Ctx) error {
+	admin := c.Locals("currentUser").(entity.User)
+
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return exception.NewBadInputError("id must be a number")
+	}
+
+	request := new(web.UpdateRoleReq)
+	err = c.BodyParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	response, err := controller.AdminService.UpdateRole(c.Context(), admin, userID, *request, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *AdminControllerImpl) SuspendUserCtrl(c *fiber.Ctx) error {
+	admin := c.Locals("currentUser").(entity.User)
+
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return exception.NewBadInputError("id must be a number")
+	}
+
+	// The reason is optional, so the body may be empty.
+	request := new(web.SuspendUserReq)
+	if len(c.Body()) > 0 {
+		err = c.BodyParser(request)
+		if err != nil {
+			return exception.NewBadInputError(err.Error())
+		}
+	}
+
+	response, err := controller.AdminService.Suspend(c.Context(), admin, userID, *request, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *AdminControllerImpl) UnsuspendUserCtrl(c *fiber.Ctx) error {
+	admin := c.Locals("currentUser").(entity.User)
+
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return exception.NewBadInputError("id must be a number")
+	}
+
+	response, err := controller.AdminService.Unsuspend(c.Context(), admin, userID, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *AdminControllerImpl) ForcePasswordResetCtrl(c *fiber.Ctx) error {
+	admin := c.Locals("currentUser").(entity.User)
+
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return exception.NewBadInputError("id must be a number")
+	}
+
+	response, err := controller.AdminService.ForcePasswordReset(c.Context(), admin, userID, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *AdminControllerImpl) UnlockUserCtrl(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return exception.NewBadInputError("id must be a number")
+	}
+
+	err = controller.LoginThrottleService.Unlock(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   "user unlocked successfully",
+	})
+}
@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type AuditController interface {
+	GetAllAuditEventCtrl(c *fiber.Ctx) error
+	GetSecurityActivityCtrl(c *fiber.Ctx) error
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"meals-app/exception"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditControllerImpl struct {
+	AuditService service.AuditService
+}
+
+func NewAuditControllerImpl(auditService service.AuditService) AuditController {
+	return &AuditControllerImpl{
+		AuditService: auditService,
+	}
+}
+
+func (controller *AuditControllerImpl) GetAllAuditEventCtrl(c *fiber.Ctx) error {
+	request := new(web.SearchAuditEventsReq)
+	err := c.QueryParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	responses, meta, err := controller.AuditService.FindAll(c.Context(), *request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   responses,
+		"meta":   meta,
+	})
+}
+
+func (controller *AuditControllerImpl) GetSecurityActivityCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	responses, err := controller.AuditService.FindRecentByUser(c.Context(), user)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   responses,
+	})
+}
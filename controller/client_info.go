@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"meals-app/model/web"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func clientInfo(c *fiber.Ctx) web.ClientInfo {
+	return web.ClientInfo{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
+	}
+}
@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type HealthController interface {
+	LivenessCtrl(c *fiber.Ctx) error
+	ReadinessCtrl(c *fiber.Ctx) error
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"meals-app/storage"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// imageStoreCheckInterval is how long readiness reuses the result of the
+// last image store ping. Cloudinary rate limits its ping, and probes
+// arrive every few seconds.
+const imageStoreCheckInterval = time.Minute
+
+type HealthControllerImpl struct {
+	DB         *gorm.DB
+	ImageStore storage.ImageStore
+
+	mu                  sync.Mutex
+	imageStoreCheckedAt time.Time
+	imageStoreErr       error
+}
+
+func NewHealthControllerImpl(DB *gorm.DB, imageStore storage.ImageStore) HealthController {
+	return &HealthControllerImpl{
+		DB:         DB,
+		ImageStore: imageStore,
+	}
+}
+
+func (controller *HealthControllerImpl) LivenessCtrl(c *fiber.Ctx) error {
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   "ok",
+	})
+}
+
+func (controller *HealthControllerImpl) ReadinessCtrl(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+	defer cancel()
+
+	checks := fiber.Map{
+		"database":    "ok",
+		"image_store": "ok",
+	}
+	ready := true
+
+	sqlDB, err := controller.DB.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		checks["database"] = err.Error()
+		ready = false
+	}
+
+	err = controller.pingImageStore(ctx)
+	if err != nil {
+		checks["image_store"] = err.Error()
+		ready = false
+	}
+
+	if !ready {
+		return c.Status(503).JSON(fiber.Map{
+			"code":   503,
+			"status": "SERVICE UNAVAILABLE",
+			"data": fiber.Map{
+				"error":  "service is not ready",
+				"checks": checks,
+			},
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   checks,
+	})
+}
+
+func (controller *HealthControllerImpl) pingImageStore(ctx context.Context) error {
+	controller.mu.Lock()
+	defer controller.mu.Unlock()
+
+	if time.Since(controller.imageStoreCheckedAt) < imageStoreCheckInterval {
+		return controller.imageStoreErr
+	}
+
+	controller.imageStoreErr = controller.ImageStore.Ping(ctx)
+	controller.imageStoreCheckedAt = time.Now()
+	return controller.imageStoreErr
+}
@@ -0,0 +1,7 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type KeyController interface {
+	JWKSCtrl(c *fiber.Ctx) error
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"meals-app/signing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type KeyControllerImpl struct {
+	KeySet *signing.KeySet
+}
+
+func NewKeyControllerImpl(keySet *signing.KeySet) KeyController {
+	return &KeyControllerImpl{
+		KeySet: keySet,
+	}
+}
+
+// JWKSCtrl publishes the verification keys in the standard JWK Set format
+// rather than the usual response envelope, so other services can verify
+// access tokens.
+func (controller *KeyControllerImpl) JWKSCtrl(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(200).JSON(controller.KeySet.JWKS())
+}
@@ -9,6 +9,8 @@ type MealController interface {
 	UpdateMealCtrl(c *fiber.Ctx) error
 	UpdateMealImageCtrl(c *fiber.Ctx) error
 	DeleteMealCtrl(c *fiber.Ctx) error
+	HideMealCtrl(c *fiber.Ctx) error
+	UnhideMealCtrl(c *fiber.Ctx) error
 	AddToFavoriteCtrl(c *fiber.Ctx) error
 	DeleteFromFavoriteCtrl(c *fiber.Ctx) error
 }
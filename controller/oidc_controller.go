@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type OIDCController interface {
+	LoginCtrl(c *fiber.Ctx) error
+	CallbackCtrl(c *fiber.Ctx) error
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"meals-app/exception"
+	"meals-app/model/web"
+	"meals-app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OIDCControllerImpl struct {
+	OIDCService service.OIDCService
+}
+
+func NewOIDCControllerImpl(oidcService service.OIDCService) OIDCController {
+	return &OIDCControllerImpl{
+		OIDCService: oidcService,
+	}
+}
+
+func (controller *OIDCControllerImpl) LoginCtrl(c *fiber.Ctx) error {
+	authURL, err := controller.OIDCService.AuthURL(c.Context(), c.Params("provider"))
+	if err != nil {
+		return err
+	}
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+func (controller *OIDCControllerImpl) CallbackCtrl(c *fiber.Ctx) error {
+	request := new(web.OIDCCallbackReq)
+	err := c.QueryParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	response, err := controller.OIDCService.Callback(c.Context(), c.Params("provider"), *request, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
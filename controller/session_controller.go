@@ -0,0 +1,9 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type SessionController interface {
+	LogoutCtrl(c *fiber.Ctx) error
+	GetAllSessionCtrl(c *fiber.Ctx) error
+	DeleteSessionCtrl(c *fiber.Ctx) error
+}
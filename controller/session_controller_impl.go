@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"meals-app/model/entity"
+	"meals-app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionControllerImpl struct {
+	SessionService service.SessionService
+}
+
+func NewSessionControllerImpl(sessionService service.SessionService) SessionController {
+	return &SessionControllerImpl{
+		SessionService: sessionService,
+	}
+}
+
+func (controller *SessionControllerImpl) LogoutCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+	session := c.Locals("currentSession").(entity.Session)
+
+	err := controller.SessionService.Revoke(c.Context(), user, session.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   "logged out successfully",
+	})
+}
+
+func (controller *SessionControllerImpl) GetAllSessionCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+	session := c.Locals("currentSession").(entity.Session)
+
+	responses, err := controller.SessionService.FindAll(c.Context(), user, session.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   responses,
+	})
+}
+
+func (controller *SessionControllerImpl) DeleteSessionCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	err := controller.SessionService.Revoke(c.Context(), user, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   "session revoked successfully",
+	})
+}
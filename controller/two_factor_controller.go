@@ -0,0 +1,11 @@
+package controller
+
+import "github.com/gofiber/fiber/v2"
+
+type TwoFactorController interface {
+	EnrollCtrl(c *fiber.Ctx) error
+	ConfirmCtrl(c *fiber.Ctx) error
+	RegenerateRecoveryCodesCtrl(c *fiber.Ctx) error
+	DisableCtrl(c *fiber.Ctx) error
+	VerifyCtrl(c *fiber.Ctx) error
+}
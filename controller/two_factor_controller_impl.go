@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"meals-app/exception"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TwoFactorControllerImpl struct {
+	TwoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorControllerImpl(twoFactorService service.TwoFactorService) TwoFactorController {
+	return &TwoFactorControllerImpl{
+		TwoFactorService: twoFactorService,
+	}
+}
+
+func (controller *TwoFactorControllerImpl) EnrollCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	request := new(web.TwoFactorEnrollReq)
+	err := c.BodyParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	response, err := controller.TwoFactorService.Enroll(c.Context(), user, *request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *TwoFactorControllerImpl) ConfirmCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	request := new(web.TwoFactorConfirmReq)
+	err := c.BodyParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	response, err := controller.TwoFactorService.Confirm(c.Context(), user, *request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *TwoFactorControllerImpl) RegenerateRecoveryCodesCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	request := new(web.TwoFactorCodeReq)
+	err := c.BodyParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	response, err := controller.TwoFactorService.RegenerateRecoveryCodes(c.Context(), user, *request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
+
+func (controller *TwoFactorControllerImpl) DisableCtrl(c *fiber.Ctx) error {
+	user := c.Locals("currentUser").(entity.User)
+
+	request := new(web.DisableTwoFactorReq)
+	err := c.BodyParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	err = controller.TwoFactorService.Disable(c.Context(), user, *request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   "two-factor authentication disabled",
+	})
+}
+
+func (controller *TwoFactorControllerImpl) VerifyCtrl(c *fiber.Ctx) error {
+	request := new(web.TwoFactorLoginReq)
+	err := c.BodyParser(request)
+	if err != nil {
+		return exception.NewBadInputError(err.Error())
+	}
+
+	response, err := controller.TwoFactorService.Verify(c.Context(), *request, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"code":   200,
+		"status": "success",
+		"data":   response,
+	})
+}
@@ -4,7 +4,12 @@ import "github.com/gofiber/fiber/v2"
 
 type UserController interface {
 	RegisterCtrl(c *fiber.Ctx) error
+	VerifyEmailCtrl(c *fiber.Ctx) error
+	ResendVerificationCtrl(c *fiber.Ctx) error
+	ForgotPasswordCtrl(c *fiber.Ctx) error
+	ResetPasswordCtrl(c *fiber.Ctx) error
 	LoginCtrl(c *fiber.Ctx) error
+	RefreshTokenCtrl(c *fiber.Ctx) error
 	ProfileCtrl(c *fiber.Ctx) error
 	UpdateProfileCtrl(c *fiber.Ctx) error
 	UpdatePasswordCtrl(c *fiber.Ctx) error
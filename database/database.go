@@ -1,20 +1,57 @@
 package database
 
 import (
+	"fmt"
 	"log"
-	"meals-app/helper"
-	"os"
+	"meals-app/config"
+	"strings"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func DatabaseInit() *gorm.DB {
-	dsn := os.Getenv("DB_URL")
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
-	helper.PanicError(err)
+func DatabaseInit(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	dialector, err := NewDialector(cfg.Driver, cfg.URL)
+	if err != nil {
+		return nil, err
+	}
 
-	log.Println("Connected to database")
+	db, err := gorm.Open(dialector, &gorm.Config{
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return db
+	log.Printf("Connected to %s database", db.Dialector.Name())
+
+	return db, nil
+}
+
+func NewDialector(driver string, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "", "mysql":
+		return mysql.Open(dsn), nil
+	case "postgres":
+		return postgres.Open(dsn), nil
+	case "sqlite":
+		return sqlite.Open(withForeignKeys(dsn)), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// withForeignKeys turns on foreign key enforcement, which SQLite leaves off
+// for every new connection and which the ON DELETE CASCADE clauses of the
+// migrations rely on.
+func withForeignKeys(dsn string) string {
+	if strings.Contains(dsn, "foreign_keys") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_pragma=foreign_keys(1)"
+	}
+	return dsn + "?_pragma=foreign_keys(1)"
 }
@@ -0,0 +1,222 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type SchemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// LoadMigrations reads the embedded migrations for the given dialect,
+// ordered by version. Files are named <version>_<name>.<up|down>.sql.
+func LoadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q", dialect)
+	}
+
+	migrations := map[int64]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(fileName, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %q", fileName)
+		}
+
+		rawVersion, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", fileName)
+		}
+
+		version, err := strconv.ParseInt(rawVersion, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q", fileName)
+		}
+
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := migrations[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			migrations[version] = migration
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var result []Migration
+	for _, migration := range migrations {
+		result = append(result, *migration)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// MigrateUp applies every pending migration and returns how many ran.
+func MigrateUp(db *gorm.DB) (int, error) {
+	statuses, err := GetMigrationStatus(db)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, status := range statuses {
+		if status.Applied {
+			continue
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			err := execStatements(tx, status.Up)
+			if err != nil {
+				return err
+			}
+
+			return tx.Create(&SchemaMigration{
+				Version:   status.Version,
+				Name:      status.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %d_%s: %w", status.Version, status.Name, err)
+		}
+
+		applied++
+	}
+
+	return applied, nil
+}
+
+// MigrateDown rolls back up to steps of the most recently applied
+// migrations and returns how many were rolled back.
+func MigrateDown(db *gorm.DB, steps int) (int, error) {
+	statuses, err := GetMigrationStatus(db)
+	if err != nil {
+		return 0, err
+	}
+
+	rolledBack := 0
+	for i := len(statuses) - 1; i >= 0 && rolledBack < steps; i-- {
+		status := statuses[i]
+		if !status.Applied {
+			continue
+		}
+
+		if status.Down == "" {
+			return rolledBack, fmt.Errorf("migration %d_%s has no down migration", status.Version, status.Name)
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			err := execStatements(tx, status.Down)
+			if err != nil {
+				return err
+			}
+
+			return tx.Delete(&SchemaMigration{}, "version = ?", status.Version).Error
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("migration %d_%s: %w", status.Version, status.Name, err)
+		}
+
+		rolledBack++
+	}
+
+	return rolledBack, nil
+}
+
+func GetMigrationStatus(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.AutoMigrate(&SchemaMigration{})
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []SchemaMigration
+	err = db.Find(&applied).Error
+	if err != nil {
+		return nil, err
+	}
+
+	appliedAt := map[int64]time.Time{}
+	for _, schemaMigration := range applied {
+		appliedAt[schemaMigration.Version] = schemaMigration.AppliedAt
+	}
+
+	var statuses []MigrationStatus
+	for _, migration := range migrations {
+		at, ok := appliedAt[migration.Version]
+		statuses = append(statuses, MigrationStatus{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: at,
+		})
+	}
+
+	return statuses, nil
+}
+
+// execStatements runs a migration file one statement at a time, since not
+// every driver accepts several statements in a single Exec.
+func execStatements(tx *gorm.DB, script string) error {
+	for _, statement := range strings.Split(script, ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+
+		err := tx.Exec(statement).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+CREATE TABLE users (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    username VARCHAR(100) NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'user',
+    password VARCHAR(255) NOT NULL,
+    image_url TEXT,
+    token_version INT NOT NULL DEFAULT 1,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    PRIMARY KEY (id),
+    UNIQUE KEY users_email_unique (email)
+) ENGINE = InnoDB;
@@ -0,0 +1,18 @@
+CREATE TABLE meal_recipes (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    category VARCHAR(100) NOT NULL,
+    image_url TEXT,
+    duration VARCHAR(50) NOT NULL,
+    complexity VARCHAR(50) NOT NULL,
+    affordability VARCHAR(50) NOT NULL,
+    is_gluten_free BOOLEAN NOT NULL DEFAULT FALSE,
+    is_lactose_free BOOLEAN NOT NULL DEFAULT FALSE,
+    is_vegan BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    PRIMARY KEY (id),
+    FULLTEXT KEY meal_recipes_name_fulltext (name),
+    CONSTRAINT meal_recipes_user_id_foreign FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE = InnoDB;
@@ -0,0 +1,9 @@
+CREATE TABLE meal_recipe_steps (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    meal_recipe_id BIGINT NOT NULL,
+    step TEXT NOT NULL,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    PRIMARY KEY (id),
+    CONSTRAINT meal_recipe_steps_meal_recipe_id_foreign FOREIGN KEY (meal_recipe_id) REFERENCES meal_recipes (id) ON DELETE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,7 @@
+CREATE TABLE favorite_user_meal (
+    user_id BIGINT NOT NULL,
+    meal_recipe_id BIGINT NOT NULL,
+    PRIMARY KEY (user_id, meal_recipe_id),
+    CONSTRAINT favorite_user_meal_user_id_foreign FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT favorite_user_meal_meal_recipe_id_foreign FOREIGN KEY (meal_recipe_id) REFERENCES meal_recipes (id) ON DELETE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,13 @@
+CREATE TABLE user_tokens (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    purpose VARCHAR(30) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at DATETIME(3) NOT NULL,
+    used_at DATETIME(3) NULL,
+    created_at DATETIME(3),
+    PRIMARY KEY (id),
+    UNIQUE KEY user_tokens_token_hash_unique (token_hash),
+    KEY user_tokens_user_id_purpose_index (user_id, purpose),
+    CONSTRAINT user_tokens_user_id_foreign FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB;
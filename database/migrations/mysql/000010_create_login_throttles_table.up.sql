@@ -0,0 +1,7 @@
+CREATE TABLE login_throttles (
+    subject VARCHAR(191) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failed_at DATETIME(3) NOT NULL,
+    locked_until DATETIME(3) NULL,
+    PRIMARY KEY (subject)
+) ENGINE = InnoDB;
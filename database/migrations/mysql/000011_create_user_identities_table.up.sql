@@ -0,0 +1,12 @@
+CREATE TABLE user_identities (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(191) NOT NULL,
+    email VARCHAR(100),
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    PRIMARY KEY (id),
+    UNIQUE KEY user_identities_provider_subject_unique (provider, subject),
+    CONSTRAINT user_identities_user_id_foreign FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB;
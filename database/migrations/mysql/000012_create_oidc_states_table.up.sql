@@ -0,0 +1,9 @@
+CREATE TABLE oidc_states (
+    state_hash VARCHAR(64) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    expires_at DATETIME(3) NOT NULL,
+    created_at DATETIME(3),
+    PRIMARY KEY (state_hash)
+) ENGINE = InnoDB;
@@ -0,0 +1,15 @@
+CREATE TABLE personal_access_tokens (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    expires_at DATETIME(3) NOT NULL,
+    last_used_at DATETIME(3) NULL,
+    revoked_at DATETIME(3) NULL,
+    created_at DATETIME(3),
+    PRIMARY KEY (id),
+    UNIQUE KEY personal_access_tokens_token_hash_unique (token_hash),
+    KEY personal_access_tokens_user_id_index (user_id),
+    CONSTRAINT personal_access_tokens_user_id_foreign FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE = InnoDB;
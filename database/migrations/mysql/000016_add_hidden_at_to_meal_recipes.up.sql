@@ -0,0 +1 @@
+ALTER TABLE meal_recipes ADD COLUMN hidden_at DATETIME(3) NULL AFTER is_vegan;
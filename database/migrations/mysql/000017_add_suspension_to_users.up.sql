@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN suspended_at DATETIME(3) NULL AFTER totp_last_step,
+    ADD COLUMN suspension_reason VARCHAR(255) NULL AFTER suspended_at,
+    ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE AFTER suspension_reason;
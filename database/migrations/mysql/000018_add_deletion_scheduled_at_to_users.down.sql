@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN deletion_scheduled_at;
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN deletion_scheduled_at DATETIME(3) NULL AFTER password_reset_required;
@@ -0,0 +1,17 @@
+CREATE TABLE audit_events (
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    action VARCHAR(50) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    actor_id BIGINT NULL,
+    target_type VARCHAR(20) NULL,
+    target_id BIGINT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    detail VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME(3) NOT NULL,
+    PRIMARY KEY (id),
+    KEY audit_events_actor_id_index (actor_id),
+    KEY audit_events_target_index (target_type, target_id),
+    KEY audit_events_action_index (action),
+    KEY audit_events_created_at_index (created_at)
+) ENGINE = InnoDB;
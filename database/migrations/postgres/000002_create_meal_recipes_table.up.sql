@@ -0,0 +1,17 @@
+CREATE TABLE meal_recipes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users (id),
+    name VARCHAR(100) NOT NULL,
+    category VARCHAR(100) NOT NULL,
+    image_url TEXT,
+    duration VARCHAR(50) NOT NULL,
+    complexity VARCHAR(50) NOT NULL,
+    affordability VARCHAR(50) NOT NULL,
+    is_gluten_free BOOLEAN NOT NULL DEFAULT FALSE,
+    is_lactose_free BOOLEAN NOT NULL DEFAULT FALSE,
+    is_vegan BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX meal_recipes_user_id_index ON meal_recipes (user_id);
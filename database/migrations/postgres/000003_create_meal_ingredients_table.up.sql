@@ -0,0 +1,7 @@
+CREATE TABLE meal_ingredients (
+    id BIGSERIAL PRIMARY KEY,
+    meal_recipe_id BIGINT NOT NULL REFERENCES meal_recipes (id) ON DELETE CASCADE,
+    ingredient TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
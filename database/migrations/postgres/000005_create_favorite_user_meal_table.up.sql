@@ -0,0 +1,5 @@
+CREATE TABLE favorite_user_meal (
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    meal_recipe_id BIGINT NOT NULL REFERENCES meal_recipes (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, meal_recipe_id)
+);
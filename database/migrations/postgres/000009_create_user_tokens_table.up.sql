@@ -0,0 +1,12 @@
+CREATE TABLE user_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ,
+    CONSTRAINT user_tokens_token_hash_unique UNIQUE (token_hash)
+);
+
+CREATE INDEX user_tokens_user_id_purpose_index ON user_tokens (user_id, purpose);
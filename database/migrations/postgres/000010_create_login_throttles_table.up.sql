@@ -0,0 +1,6 @@
+CREATE TABLE login_throttles (
+    subject VARCHAR(191) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE meal_recipes DROP COLUMN hidden_at;
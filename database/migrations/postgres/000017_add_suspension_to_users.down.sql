@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN suspended_at,
+    DROP COLUMN suspension_reason,
+    DROP COLUMN password_reset_required;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN suspended_at TIMESTAMPTZ NULL,
+    ADD COLUMN suspension_reason VARCHAR(255) NULL,
+    ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,17 @@
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(50) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    actor_id BIGINT NULL,
+    target_type VARCHAR(20) NULL,
+    target_id BIGINT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    detail VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX audit_events_actor_id_index ON audit_events (actor_id);
+CREATE INDEX audit_events_target_index ON audit_events (target_type, target_id);
+CREATE INDEX audit_events_action_index ON audit_events (action);
+CREATE INDEX audit_events_created_at_index ON audit_events (created_at);
@@ -0,0 +1,7 @@
+CREATE TABLE meal_ingredients (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    meal_recipe_id INTEGER NOT NULL REFERENCES meal_recipes (id) ON DELETE CASCADE,
+    ingredient TEXT NOT NULL,
+    created_at DATETIME,
+    updated_at DATETIME
+);
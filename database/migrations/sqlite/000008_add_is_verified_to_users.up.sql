@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN is_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Accounts created before verification existed stay usable.
+UPDATE users SET is_verified = TRUE;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN suspended_at DATETIME NULL;
+ALTER TABLE users ADD COLUMN suspension_reason VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,136 @@
+package exception
+
+import "time"
+
+var (
+	ErrMealNotFound         = NewNotFoundError("meal recipe not found")
+	ErrFavoriteNotFound     = NewNotFoundError("meal recipe is not in favorites")
+	ErrForbidden            = NewForbiddenError("forbidden, you are not allowed")
+	ErrEmailExists          = NewConflictError("email already exists")
+	ErrInvalidCredentials   = &UnauthorizedError{Message: "invalid credentials", Status: "INVALID CREDENTIALS"}
+	ErrInvalidImageFormat   = NewBadInputError("image format must be jpg, jpeg or png")
+	ErrInvalidRefreshToken  = NewUnauthorizedError("invalid refresh token")
+	ErrRefreshTokenReused   = NewUnauthorizedError("refresh token reuse detected, login instead")
+	ErrSessionNotFound      = NewNotFoundError("session not found")
+	ErrEmailNotVerified     = NewForbiddenError("verify your email address first")
+	ErrInvalidVerification  = NewBadInputError("invalid or expired verification token")
+	ErrAlreadyVerified      = NewConflictError("email address is already verified")
+	ErrInvalidPasswordReset = NewBadInputError("invalid or expired password reset token")
+	ErrWrongPassword        = NewBadInputError("current password is incorrect")
+	ErrUserNotFound         = NewNotFoundError("user not found")
+	ErrProviderNotFound     = NewNotFoundError("identity provider not found")
+	ErrInvalidOIDCState     = NewBadInputError("invalid or expired login state, start the login again")
+	ErrOIDCLoginFailed      = NewUnauthorizedError("identity provider login failed")
+	ErrOIDCEmailNotVerified = NewForbiddenError("identity provider has not verified the email address")
+	ErrUnverifiedAccount    = NewConflictError("an unverified account already uses this email address, reset its password to claim it")
+	ErrTwoFactorEnabled     = NewConflictError("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled  = NewBadInputError("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled = NewBadInputError("start two-factor enrollment first")
+	ErrInvalidTwoFactorCode = NewBadInputError("invalid two-factor code")
+	ErrTwoFactorLoginFailed = NewUnauthorizedError("invalid two-factor code")
+	ErrInvalidChallenge     = NewUnauthorizedError("invalid or expired login challenge, login again")
+	ErrAccessTokenNotFound  = NewNotFoundError("access token not found")
+	ErrAccountSuspended     = NewForbiddenError("account suspended")
+	ErrPasswordResetNeeded  = NewForbiddenError("a password reset is required, check your email")
+	ErrManageSelf           = NewBadInputError("you cannot change the role of or suspend your own account")
+	ErrAlreadySuspended     = NewConflictError("user is already suspended")
+	ErrNotSuspended         = NewConflictError("user is not suspended")
+	ErrDeletionScheduled    = NewConflictError("account deletion is already scheduled")
+	ErrDeletionNotScheduled = NewBadInputError("account deletion is not scheduled")
+	ErrInvalidDeletionToken = NewBadInputError("invalid or expired account deletion token")
+	ErrInvalidCursor        = NewBadInputError("invalid cursor, start again from the first page")
+)
+
+type NotFoundError struct {
+	Message string
+}
+
+func NewNotFoundError(message string) *NotFoundError {
+	return &NotFoundError{Message: message}
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+type ForbiddenError struct {
+	Message string
+}
+
+func NewForbiddenError(message string) *ForbiddenError {
+	return &ForbiddenError{Message: message}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+type UnauthorizedError struct {
+	Message string
+	Status  string
+}
+
+func NewUnauthorizedError(message string) *UnauthorizedError {
+	return &UnauthorizedError{Message: message, Status: "UNAUTHORIZE"}
+}
+
+func (e *UnauthorizedError) Error() string {
+	return e.Message
+}
+
+type ConflictError struct {
+	Message string
+}
+
+func NewConflictError(message string) *ConflictError {
+	return &ConflictError{Message: message}
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// TooManyRequestsError tells the client to wait RetryAfter before trying
+// again.
+type TooManyRequestsError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func NewTooManyRequestsError(message string, retryAfter time.Duration) *TooManyRequestsError {
+	return &TooManyRequestsError{Message: message, RetryAfter: retryAfter}
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return e.Message
+}
+
+type BadInputError struct {
+	Message string
+}
+
+func NewBadInputError(message string) *BadInputError {
+	return &BadInputError{Message: message}
+}
+
+func (e *BadInputError) Error() string {
+	return e.Message
+}
+
+// ValidationError wraps the error returned by the validator so the
+// central error handler can answer with 422.
+type ValidationError struct {
+	Err error
+}
+
+func NewValidationError(err error) *ValidationError {
+	return &ValidationError{Err: err}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
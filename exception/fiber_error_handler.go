@@ -0,0 +1,105 @@
+package exception
+
+import (
+	"errors"
+	"log"
+	"math"
+	"meals-app/model/web"
+	"strconv"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// errInternal replaces the message of unexpected errors, which may come
+// straight from the database or an upstream API, before it reaches clients.
+var errInternal = errors.New("internal server error")
+
+// NewFiberErrorHandler maps errors returned from handlers and middleware
+// to the {code, status, data.error} envelope. Anything that is not a known
+// client error is logged and answered with a generic 500.
+func NewFiberErrorHandler(uni *ut.UniversalTranslator) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		var notFoundErr *NotFoundError
+		var forbiddenErr *ForbiddenError
+		var unauthorizedErr *UnauthorizedError
+		var conflictErr *ConflictError
+		var badInputErr *BadInputError
+		var tooManyRequestsErr *TooManyRequestsError
+		var validationErr *ValidationError
+		var fiberErr *fiber.Error
+
+		switch {
+		case errors.As(err, &validationErr):
+			return validationErrorHandler(c, uni, validationErr)
+		case errors.As(err, &badInputErr):
+			return ErrorHandler(400, "BAD REQUEST", err)(c)
+		case errors.As(err, &unauthorizedErr):
+			return ErrorHandler(401, unauthorizedErr.Status, err)(c)
+		case errors.As(err, &forbiddenErr):
+			return ErrorHandler(403, "FORBIDDEN", err)(c)
+		case errors.As(err, &notFoundErr):
+			return ErrorHandler(404, "NOT FOUND", err)(c)
+		case errors.As(err, &conflictErr):
+			return ErrorHandler(409, "DUPLICATE ENTRY", err)(c)
+		case errors.As(err, &tooManyRequestsErr):
+			retryAfter := int(math.Ceil(tooManyRequestsErr.RetryAfter.Seconds()))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return ErrorHandler(429, "TOO MANY REQUESTS", err)(c)
+		case errors.As(err, &fiberErr) && fiberErr.Code < 500:
+			return ErrorHandler(fiberErr.Code, strings.ToUpper(utils.StatusMessage(fiberErr.Code)), err)(c)
+		}
+
+		log.Printf("%s %s: %v", c.Method(), c.OriginalURL(), err)
+
+		return ErrorHandler(500, "INTERNAL SERVER ERROR", errInternal)(c)
+	}
+}
+
+// validationErrorHandler lists every failed rule with a message in the
+// language requested through Accept-Language.
+func validationErrorHandler(c *fiber.Ctx, uni *ut.UniversalTranslator, err *ValidationError) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) || len(validationErrors) == 0 {
+		return ErrorHandler(422, "VALIDATION ERROR", err)(c)
+	}
+
+	trans := translator(c, uni)
+
+	fields := []web.FieldErrorResponse{}
+	for _, fieldErr := range validationErrors {
+		fields = append(fields, web.FieldErrorResponse{
+			Field:   fieldErr.Field(),
+			Rule:    fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Message: fieldErr.Translate(trans),
+		})
+	}
+
+	return c.Status(422).JSON(fiber.Map{
+		"code":   422,
+		"status": "VALIDATION ERROR",
+		"data": fiber.Map{
+			"error":  fields[0].Message,
+			"fields": fields,
+		},
+	})
+}
+
+func translator(c *fiber.Ctx, uni *ut.UniversalTranslator) ut.Translator {
+	var locales []string
+	for _, lang := range strings.Split(c.Get(fiber.HeaderAcceptLanguage), ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(lang), ";")
+		if tag == "" {
+			continue
+		}
+		base, _, _ := strings.Cut(tag, "-")
+		locales = append(locales, strings.ToLower(base))
+	}
+
+	trans, _ := uni.FindTranslator(locales...)
+	return trans
+}
@@ -0,0 +1,162 @@
+package helper
+
+import (
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+func ToUserResponse(user entity.User) web.UserResponse {
+	return web.UserResponse{
+		ID:                  user.ID,
+		Username:            user.Username,
+		Email:               user.Email,
+		Role:                user.Role,
+		Permissions:         entity.RolePermissions(user.Role),
+		Image:               user.ImageUrl,
+		IsVerified:          user.IsVerified,
+		TwoFactor:           user.TOTPEnabled,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+	}
+}
+
+func ToAdminUserResponse(user entity.User) web.AdminUserResponse {
+	return web.AdminUserResponse{
+		UserResponse:          ToUserResponse(user),
+		SuspendedAt:           user.SuspendedAt,
+		SuspensionReason:      user.SuspensionReason,
+		PasswordResetRequired: user.PasswordResetRequired,
+	}
+}
+
+func ToMealResponse(meal entity.MealRecipe) web.MealResponse {
+	var ingredients []string
+	for _, ingredient := range meal.Ingredients {
+		ingredients = append(ingredients, ingredient.Ingredient)
+	}
+
+	var steps []string
+	for _, step := range meal.Steps {
+		steps = append(steps, step.Step)
+	}
+
+	return web.MealResponse{
+		ID:            meal.ID,
+		UserId:        meal.UserId,
+		Name:          meal.Name,
+		Category:      meal.Category,
+		ImageUrl:      meal.ImageUrl,
+		Duration:      meal.Duration,
+		Complexity:    meal.Complexity,
+		Affordability: meal.Affordability,
+		IsGlutenFree:  meal.IsGlutenFree,
+		IsLactoseFree: meal.IsLactoseFree,
+		IsVegan:       meal.IsVegan,
+		IsHidden:      meal.HiddenAt != nil,
+		Ingredients:   ingredients,
+		Steps:         steps,
+		CreatedAt:     meal.CreatedAt,
+		UpdatedAt:     meal.UpdatedAt,
+	}
+}
+
+func ToMealResponses(meals []entity.MealRecipe) []web.MealResponse {
+	var responses []web.MealResponse
+	for _, meal := range meals {
+		responses = append(responses, ToMealResponse(meal))
+	}
+	return responses
+}
+
+func ToMealSummaryResponse(meal entity.MealRecipe) web.MealSummaryResponse {
+	var ingredients []string
+	for _, ingredient := range meal.Ingredients {
+		ingredients = append(ingredients, ingredient.Ingredient)
+	}
+
+	var steps []string
+	for _, step := range meal.Steps {
+		steps = append(steps, step.Step)
+	}
+
+	return web.MealSummaryResponse{
+		ID:            meal.ID,
+		UserId:        meal.UserId,
+		Name:          meal.Name,
+		Category:      meal.Category,
+		ImageUrl:      meal.ImageUrl,
+		Duration:      meal.Duration,
+		Complexity:    meal.Complexity,
+		Affordability: meal.Affordability,
+		IsGlutenFree:  meal.IsGlutenFree,
+		IsLactoseFree: meal.IsLactoseFree,
+		IsVegan:       meal.IsVegan,
+		FavoriteCount: meal.FavoriteCount,
+		Ingredients:   ingredients,
+		Steps:         steps,
+		CreatedAt:     meal.CreatedAt,
+		UpdatedAt:     meal.UpdatedAt,
+	}
+}
+
+func ToMealSummaryResponses(meals []entity.MealRecipe) []web.MealSummaryResponse {
+	responses := []web.MealSummaryResponse{}
+	for _, meal := range meals {
+		responses = append(responses, ToMealSummaryResponse(meal))
+	}
+	return responses
+}
+
+func ToAccessTokenResponse(token entity.PersonalAccessToken) web.AccessTokenResponse {
+	return web.AccessTokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.ScopeList(),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+// ToSecurityActivityResponses shows userID the events on their account,
+// leaving out who else acted on it and from where.
+func ToSecurityActivityResponses(events []entity.AuditEvent, userID int) []web.SecurityActivityResponse {
+	responses := []web.SecurityActivityResponse{}
+	for _, event := range events {
+		response := web.SecurityActivityResponse{
+			ID:        event.ID,
+			Action:    event.Action,
+			Outcome:   event.Outcome,
+			Detail:    event.Detail,
+			CreatedAt: event.CreatedAt,
+		}
+		if event.ActorID != nil && *event.ActorID != userID {
+			response.ByAdmin = true
+		} else {
+			response.IPAddress = event.IPAddress
+			response.UserAgent = event.UserAgent
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+func ToAuditEventResponses(events []entity.AuditEvent) []web.AuditEventResponse {
+	responses := []web.AuditEventResponse{}
+	for _, event := range events {
+		responses = append(responses, web.AuditEventResponse{
+			ID:         event.ID,
+			Action:     event.Action,
+			Outcome:    event.Outcome,
+			ActorID:    event.ActorID,
+			TargetType: event.TargetType,
+			TargetID:   event.TargetID,
+			IPAddress:  event.IPAddress,
+			UserAgent:  event.UserAgent,
+			Detail:     event.Detail,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,113 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// OIDCProvider talks to an OpenID Connect provider. Discovery happens on
+// first use, so a provider that is down does not stop the server starting.
+type OIDCProvider struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func NewOIDCProvider(issuerURL string, clientID string, clientSecret string, redirectURL string, scopes []string) Provider {
+	return &OIDCProvider{
+		IssuerURL:    issuerURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+	}
+}
+
+func (provider *OIDCProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	oauth, _, err := provider.discover()
+	if err != nil {
+		return "", err
+	}
+
+	return oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+func (provider *OIDCProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (Claims, error) {
+	oauth, verifier, err := provider.discover()
+	if err != nil {
+		return Claims{}, err
+	}
+
+	token, err := oauth.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return Claims{}, fmt.Errorf("exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return Claims{}, errors.New("token response has no id_token")
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return Claims{}, fmt.Errorf("verify id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return Claims{}, errors.New("id_token nonce does not match")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	return Claims{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+func (provider *OIDCProvider) discover() (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if provider.oauth != nil {
+		return provider.oauth, provider.verifier, nil
+	}
+
+	// The provider keeps the context to refresh its signing keys later, so
+	// it must outlive the request that triggered discovery.
+	discovered, err := oidc.NewProvider(context.Background(), provider.IssuerURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("discover %s: %w", provider.IssuerURL, err)
+	}
+
+	provider.oauth = &oauth2.Config{
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURL:  provider.RedirectURL,
+		Endpoint:     discovered.Endpoint(),
+		Scopes:       append([]string{oidc.ScopeOpenID}, provider.Scopes...),
+	}
+	provider.verifier = discovered.Verifier(&oidc.Config{ClientID: provider.ClientID})
+
+	return provider.oauth, provider.verifier, nil
+}
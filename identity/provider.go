@@ -0,0 +1,18 @@
+package identity
+
+import "context"
+
+// Claims are the facts about a user that a provider vouches for.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider runs the authorization code flow with PKCE against an external
+// identity provider.
+type Provider interface {
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (Claims, error)
+}
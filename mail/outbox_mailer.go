@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// OutboxMailer writes every message to a file in Dir instead of delivering
+// it, for local development and tests. With an empty Dir the message is
+// only logged.
+type OutboxMailer struct {
+	Dir  string
+	From string
+	seq  atomic.Int64
+}
+
+func NewOutboxMailer(dir string, from string) Mailer {
+	return &OutboxMailer{
+		Dir:  dir,
+		From: from,
+	}
+}
+
+func (mailer *OutboxMailer) Send(ctx context.Context, message Message) error {
+	if mailer.Dir == "" {
+		log.Printf("mail to %s: %s\n%s", message.To, message.Subject, message.Body)
+		return nil
+	}
+
+	err := os.MkdirAll(mailer.Dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%04d.eml", time.Now().UnixNano(), mailer.seq.Add(1))
+	return os.WriteFile(filepath.Join(mailer.Dir, name), format(mailer.From, message), 0644)
+}
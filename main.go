@@ -1,47 +1,130 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"meals-app/config"
-	"meals-app/controller"
 	"meals-app/database"
 	"meals-app/helper"
+	"meals-app/repository"
 	"meals-app/router"
+	"meals-app/service"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
-	"github.com/go-playground/validator/v10"
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
 func main() {
-	envErr := godotenv.Load(".env")
-	helper.PanicError(envErr)
-
-	cld := config.NewCloudinary()
-	validate := validator.New()
-	db := database.DatabaseInit()
-
-	app := fiber.New(fiber.Config{
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"code":   fiber.StatusInternalServerError,
-				"status": "Internal Server Error",
-				"data": fiber.Map{
-					"error": err.Error(),
-				},
-			})
-		},
-	})
-	app.Use(recover.New())
-
-	userController := controller.NewUserControllerImpl(db, validate, cld)
-	mealController := controller.NewMealControllerImpl(db, validate, cld)
-
-	router.SetupRouter(app, db, userController, mealController)
-
-	err := app.Listen(":3000")
+	cfg, err := config.Load()
+	helper.PanicError(err)
+
+	db, err := database.DatabaseInit(cfg.Database)
+	helper.PanicError(err)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(db, os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "purge-accounts" {
+		runPurgeAccounts(cfg, db)
+		return
+	}
+
+	imageStore, err := config.NewImageStore(cfg)
+	helper.PanicError(err)
+
+	keySet, err := config.NewKeySet(cfg.JWT)
+	helper.PanicError(err)
+
+	app := router.NewApp(cfg, db, imageStore, config.NewMailer(cfg.Mail), keySet)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		err := app.Listen(fmt.Sprintf(":%d", cfg.App.Port))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down server")
+
+	err = app.ShutdownWithTimeout(cfg.App.ShutdownTimeout)
+	if err != nil {
+		log.Printf("Shutdown: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Close()
+	}
+	if err != nil {
+		log.Printf("Close database: %v", err)
+	}
+}
+
+// runPurgeAccounts deletes the accounts whose deletion grace period has
+// passed. Run it regularly, for example from cron.
+func runPurgeAccounts(cfg *config.Config, db *gorm.DB) {
+	validate, _ := config.NewValidator(cfg.Password)
+	accountService := service.NewAccountServiceImpl(repository.NewUserRepositoryImpl(), repository.NewUserTokenRepositoryImpl(), repository.NewMealRepositoryImpl(), repository.NewLoginThrottleRepositoryImpl(), db, validate, config.NewMailer(cfg.Mail), cfg.Account)
+
+	deleted, err := accountService.PurgeDeleted(context.Background())
 	if err != nil {
 		log.Fatal(err)
 	}
+	log.Printf("Deleted %d account(s)", deleted)
+}
+
+func runMigrate(db *gorm.DB, args []string) {
+	if len(args) == 0 {
+		log.Fatal("usage: migrate up|down [steps]|status")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Applied %d migration(s)", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatal("steps must be a positive number")
+			}
+			steps = n
+		}
+
+		rolledBack, err := database.MigrateDown(db, steps)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Rolled back %d migration(s)", rolledBack)
+	case "status":
+		statuses, err := database.GetMigrationStatus(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%06d  %-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		log.Fatal("usage: migrate up|down [steps]|status")
+	}
 }
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/model/entity"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func accessTokenAuth(c *fiber.Ctx, db *gorm.DB, secret string, scopes []string) error {
+	if len(scopes) == 0 {
+		return exception.NewForbiddenError("personal access tokens cannot be used here, login instead")
+	}
+
+	token := entity.PersonalAccessToken{}
+	err := db.Take(&token, "token_hash = ?", helper.HashToken(secret)).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.NewUnauthorizedError("invalid or expired access token")
+		}
+		return err
+	}
+
+	now := time.Now()
+	if !token.Active(now) {
+		return exception.NewUnauthorizedError("invalid or expired access token")
+	}
+
+	for _, scope := range scopes {
+		if !token.HasScope(scope) {
+			return exception.NewForbiddenError(fmt.Sprintf("access token is missing the %s scope", scope))
+		}
+	}
+
+	user := entity.User{}
+	err = db.Take(&user, "id = ?", token.UserId).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.NewUnauthorizedError("user not found")
+		}
+		return err
+	}
+
+	if user.Suspended() {
+		return exception.ErrAccountSuspended
+	}
+	if user.PasswordResetRequired {
+		return exception.ErrPasswordResetNeeded
+	}
+
+	// Like sessions, only record use once a minute.
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > time.Minute {
+		token.LastUsedAt = &now
+		err = db.Model(&token).Update("last_used_at", now).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Locals("currentUser", user)
+	c.Locals("currentAccessToken", token)
+
+	return c.Next()
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"meals-app/exception"
 	"meals-app/model/entity"
-	"os"
+	"meals-app/signing"
+	"strings"
+	"time"
 
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
@@ -12,23 +14,40 @@ import (
 	"gorm.io/gorm"
 )
 
-func Protected(db *gorm.DB) fiber.Handler{
-	return jwtware.New(jwtware.Config{
-		SigningKey: jwtware.SigningKey{
-			Key: []byte(os.Getenv("JWT_TOKEN_SECRET")),
-		},
+// Protected accepts access tokens signed by any key in keySet, matched on
+// the kid header. When scopes are given it also accepts personal access
+// tokens granted every one of them; otherwise the route is for JWTs only.
+func Protected(db *gorm.DB, keySet *signing.KeySet, scopes ...string) fiber.Handler {
+	signingKeys := map[string]jwtware.SigningKey{}
+	for _, key := range keySet.Keys {
+		signingKeys[key.ID] = jwtware.SigningKey{
+			JWTAlg: keySet.Method.Alg(),
+			Key:    key.PublicKey,
+		}
+	}
+
+	jwtHandler := jwtware.New(jwtware.Config{
+		SigningKeys:  signingKeys,
 		ErrorHandler: jwtError,
 		SuccessHandler: func(c *fiber.Ctx) error {
 			return jwtSuccess(c, db)
 		},
 	})
+
+	return func(c *fiber.Ctx) error {
+		secret, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if ok && strings.HasPrefix(secret, entity.AccessTokenPrefix) {
+			return accessTokenAuth(c, db, secret, scopes)
+		}
+		return jwtHandler(c)
+	}
 }
 
 func jwtError(c *fiber.Ctx, err error) error {
-	if err.Error() == "Missing or malformed JWT" {
-		return exception.ErrorHandler(400, "BAD REQUEST", errors.New("missing or malformed JWT"))(c)
+	if errors.Is(err, jwtware.ErrJWTMissingOrMalformed) {
+		return exception.NewBadInputError("missing or malformed JWT")
 	}
-	return exception.ErrorHandler(401, "UNAUTHORIZE", errors.New("unauthorize, login instead"))(c)
+	return exception.NewUnauthorizedError("unauthorize, login instead")
 }
 
 func jwtSuccess(c *fiber.Ctx, db *gorm.DB) error {
@@ -42,16 +61,45 @@ func jwtSuccess(c *fiber.Ctx, db *gorm.DB) error {
 	err := db.Take(&user, "id = ?", userId).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return exception.ErrorHandler(401, "UNAUTHORIZE", errors.New("user not found"))(c)
+			return exception.NewUnauthorizedError("user not found")
 		}
-		return exception.ErrorHandler(500, "INTERNAL SERVER ERROR", err)(c)
+		return err
 	}
 
 	if tokenVersion != user.TokenVersion {
-		return exception.ErrorHandler(401, "UNAUTHORIZE", errors.New("token revoked"))(c)
+		return exception.NewUnauthorizedError("token revoked")
+	}
+
+	if user.Suspended() {
+		return exception.ErrAccountSuspended
+	}
+
+	sessionID, _ := claims["jti"].(string)
+	session := entity.Session{}
+	err = db.Take(&session, "id = ? AND user_id = ?", sessionID, user.ID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.NewUnauthorizedError("session revoked")
+		}
+		return err
+	}
+
+	now := time.Now()
+	if !session.Active(now) {
+		return exception.NewUnauthorizedError("session revoked")
+	}
+
+	// Only record activity once a minute so every request is not a write.
+	if now.Sub(session.LastSeenAt) > time.Minute {
+		session.LastSeenAt = now
+		err = db.Model(&session).Update("last_seen_at", now).Error
+		if err != nil {
+			return err
+		}
 	}
 
 	c.Locals("currentUser", user)
+	c.Locals("currentSession", session)
 
 	return c.Next()
 }
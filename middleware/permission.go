@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"meals-app/exception"
+	"meals-app/model/entity"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission must run after Protected and rejects users whose role
+// lacks the permission.
+func RequirePermission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := c.Locals("currentUser").(entity.User)
+		if !user.Can(permission) {
+			return exception.ErrForbidden
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// Actions recorded in the audit log.
+const (
+	AuditLogin              = "user.login"
+	AuditPasswordChange     = "user.password_change"
+	AuditPasswordReset      = "user.password_reset"
+	AuditEmailChange        = "user.email_change"
+	AuditRoleChange         = "admin.role_change"
+	AuditSuspend            = "admin.suspend"
+	AuditUnsuspend          = "admin.unsuspend"
+	AuditForcePasswordReset = "admin.force_password_reset"
+	AuditMealDelete         = "admin.meal_delete"
+)
+
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+const (
+	AuditTargetUser = "user"
+	AuditTargetMeal = "meal"
+)
+
+// AuditEvent is an append-only record of a security-relevant action.
+// ActorID is who acted, when known, and the target is the account or meal
+// acted on. Events keep no foreign keys so they outlive deleted users.
+type AuditEvent struct {
+	ID         int       `json:"id"`
+	Action     string    `json:"action"`
+	Outcome    string    `json:"outcome"`
+	ActorID    *int      `json:"actor_id"`
+	TargetType string    `json:"target_type" gorm:"default:null"`
+	TargetID   *int      `json:"target_id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Detail     string    `json:"detail"`
+	CreatedAt  time.Time `json:"created_at"`
+}
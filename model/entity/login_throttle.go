@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// LoginThrottle counts consecutive failed logins for a subject, which is
+// either an email address ("email:...") or a client IP address ("ip:...").
+// Attempts are counted before the password is checked and given back when
+// it was right, so Failures includes logins still in flight.
+type LoginThrottle struct {
+	Subject      string     `gorm:"primaryKey"`
+	Failures     int        `json:"failures"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"`
+}
+
+func (throttle LoginThrottle) Locked(now time.Time) bool {
+	return throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil)
+}
@@ -14,6 +14,8 @@ type MealRecipe struct {
 	IsGlutenFree     bool             `json:"is_gluten_free"`
 	IsLactoseFree    bool             `json:"is_lactose_free"`
 	IsVegan          bool             `json:"is_vegan"`
+	HiddenAt         *time.Time       `json:"hidden_at"`
+	FavoriteCount    int64            `gorm:"-" json:"favorite_count"`
 	CreatedAt        time.Time        `json:"created_at"`
 	UpdatedAt        time.Time        `json:"updated_at"`
 	User             User             `gorm:"foreignKey:UserId;references:ID"`
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// OIDCState remembers an authorization request between the redirect to the
+// provider and the callback. Only the hash of the state is stored.
+type OIDCState struct {
+	StateHash    string `gorm:"primaryKey"`
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+func (OIDCState) TableName() string {
+	return "oidc_states"
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// RecoveryCode stands in for a TOTP code once, for users who lost their
+// authenticator. Only its hash is stored.
+type RecoveryCode struct {
+	ID        int        `json:"id"`
+	UserId    int        `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	User      User       `gorm:"foreignKey:UserId;references:ID;OnDelete:CASCADE"`
+}
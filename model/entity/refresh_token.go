@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+type RefreshToken struct {
+	ID           int        `json:"id"`
+	UserId       int        `json:"user_id"`
+	FamilyID     string     `json:"family_id"`
+	TokenHash    string     `json:"-"`
+	TokenVersion int        `json:"token_version"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	User         User       `gorm:"foreignKey:UserId;references:ID;OnDelete:CASCADE"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "slices"
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permissions name what a role may do beyond managing its own resources.
+const (
+	PermissionUpdateAnyMeal = "meals.update_any"
+	PermissionDeleteAnyMeal = "meals.delete_any"
+	PermissionHideMeal      = "meals.hide"
+	PermissionManageUsers   = "users.manage"
+)
+
+var rolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleModerator: {
+		PermissionUpdateAnyMeal,
+		PermissionHideMeal,
+	},
+	RoleAdmin: {
+		PermissionUpdateAnyMeal,
+		PermissionDeleteAnyMeal,
+		PermissionHideMeal,
+		PermissionManageUsers,
+	},
+}
+
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RolePermissions returns the permissions granted to role, none for an
+// unknown role.
+func RolePermissions(role string) []string {
+	return slices.Clone(rolePermissions[role])
+}
+
+func (user User) Can(permission string) bool {
+	return slices.Contains(rolePermissions[user.Role], permission)
+}
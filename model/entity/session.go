@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+type Session struct {
+	ID         string     `json:"id"`
+	UserId     int        `json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	User       User       `gorm:"foreignKey:UserId;references:ID;OnDelete:CASCADE"`
+}
+
+func (session Session) Active(now time.Time) bool {
+	return session.RevokedAt == nil && now.Before(session.ExpiresAt)
+}
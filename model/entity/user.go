@@ -3,15 +3,27 @@ package entity
 import "time"
 
 type User struct {
-	ID            int          `json:"id"`
-	Username      string       `json:"username"`
-	Email         string       `json:"email"`
-	Role          string       `json:"role" gorm:"default:user"`
-	Password      string       `json:"password"`
-	ImageUrl      string       `json:"image_url"`
-	TokenVersion  int          `json:"token_version" gorm:"default:1"`
-	CreatedAt     time.Time    `json:"created_at"`
-	UpdatedAt     time.Time    `json:"update_at"`
-	MealRecipes   []MealRecipe `gorm:"foreignKey:UserId;references:ID"`
-	FavoriteMeals []MealRecipe `gorm:"many2many:favorite_user_meal;foreignKey:id;joinForeignKey:user_id;references:id;joinReferences:meal_recipe_id"`
+	ID                    int          `json:"id"`
+	Username              string       `json:"username"`
+	Email                 string       `json:"email"`
+	Role                  string       `json:"role" gorm:"default:user"`
+	Password              string       `json:"password"`
+	ImageUrl              string       `json:"image_url"`
+	IsVerified            bool         `json:"is_verified" gorm:"default:false"`
+	TokenVersion          int          `json:"token_version" gorm:"default:1"`
+	TOTPSecret            string       `json:"-" gorm:"column:totp_secret;default:null"`
+	TOTPEnabled           bool         `json:"totp_enabled" gorm:"column:totp_enabled;default:false"`
+	TOTPLastStep          int64        `json:"-" gorm:"column:totp_last_step;default:0"`
+	SuspendedAt           *time.Time   `json:"suspended_at"`
+	SuspensionReason      string       `json:"suspension_reason" gorm:"default:null"`
+	PasswordResetRequired bool         `json:"password_reset_required" gorm:"default:false"`
+	DeletionScheduledAt   *time.Time   `json:"deletion_scheduled_at"`
+	CreatedAt             time.Time    `json:"created_at"`
+	UpdatedAt             time.Time    `json:"update_at"`
+	MealRecipes           []MealRecipe `gorm:"foreignKey:UserId;references:ID"`
+	FavoriteMeals         []MealRecipe `gorm:"many2many:favorite_user_meal;foreignKey:id;joinForeignKey:user_id;references:id;joinReferences:meal_recipe_id"`
+}
+
+func (user User) Suspended() bool {
+	return user.SuspendedAt != nil
 }
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// UserIdentity links a user to an account at an external identity
+// provider, identified by the provider's subject.
+type UserIdentity struct {
+	ID        int       `json:"id"`
+	UserId    int       `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	User      User      `gorm:"foreignKey:UserId;references:ID;OnDelete:CASCADE"`
+}
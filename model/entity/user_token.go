@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeTwoFactor         = "two_factor"
+	TokenPurposeAccountDeletion   = "account_deletion"
+)
+
+// UserToken is a single-use secret handed to a user, such as an email
+// verification link, a password reset token or a two-factor login
+// challenge. Only its hash is stored.
+type UserToken struct {
+	ID        int        `json:"id"`
+	UserId    int        `json:"user_id"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	User      User       `gorm:"foreignKey:UserId;references:ID;OnDelete:CASCADE"`
+}
@@ -0,0 +1,15 @@
+package web
+
+import "time"
+
+// AccessTokenResponse describes a personal access token. Token holds the
+// secret and is only filled in when the token is created.
+type AccessTokenResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Token      string     `json:"token,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package web
+
+import "time"
+
+type AccountDeletionResponse struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// AccountExport is everything the app stores about a user that they can
+// take with them.
+type AccountExport struct {
+	ExportedAt time.Time      `json:"exported_at"`
+	Profile    UserResponse   `json:"profile"`
+	Recipes    []MealResponse `json:"recipes"`
+	Favorites  []MealResponse `json:"favorites"`
+}
@@ -0,0 +1,18 @@
+package web
+
+// SearchUsersReq is read from the query string of GET /api/admin/users.
+type SearchUsersReq struct {
+	Query   string `query:"q" validate:"max=100"`
+	Role    string `query:"role" validate:"omitempty,oneof=user moderator admin"`
+	Status  string `query:"status" validate:"omitempty,oneof=active suspended"`
+	Page    int    `query:"page" validate:"min=0"`
+	PerPage int    `query:"per_page" validate:"min=0,max=100"`
+}
+
+type UpdateRoleReq struct {
+	Role string `json:"role" validate:"required,oneof=user moderator admin"`
+}
+
+type SuspendUserReq struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
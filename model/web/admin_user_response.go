@@ -0,0 +1,16 @@
+package web
+
+import "time"
+
+type AdminUserResponse struct {
+	UserResponse
+	SuspendedAt           *time.Time `json:"suspended_at"`
+	SuspensionReason      string     `json:"suspension_reason,omitempty"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+}
+
+type PageMeta struct {
+	Page    int   `json:"page"`
+	PerPage int   `json:"per_page"`
+	Total   int64 `json:"total"`
+}
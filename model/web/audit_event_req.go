@@ -0,0 +1,15 @@
+package web
+
+// SearchAuditEventsReq is read from the query string of
+// GET /api/admin/audit-events. From and To are RFC 3339 timestamps.
+type SearchAuditEventsReq struct {
+	Action     string `query:"action" validate:"max=50"`
+	Outcome    string `query:"outcome" validate:"omitempty,oneof=success failure"`
+	ActorID    int    `query:"actor_id" validate:"min=0"`
+	TargetType string `query:"target_type" validate:"omitempty,oneof=user meal"`
+	TargetID   int    `query:"target_id" validate:"min=0"`
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Page       int    `query:"page" validate:"min=0"`
+	PerPage    int    `query:"per_page" validate:"min=0,max=100"`
+}
@@ -0,0 +1,30 @@
+package web
+
+import "time"
+
+type AuditEventResponse struct {
+	ID         int       `json:"id"`
+	Action     string    `json:"action"`
+	Outcome    string    `json:"outcome"`
+	ActorID    *int      `json:"actor_id"`
+	TargetType string    `json:"target_type,omitempty"`
+	TargetID   *int      `json:"target_id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Detail     string    `json:"detail,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SecurityActivityResponse is an audit event as the user it concerns sees
+// it. Events caused by an admin only say so, without the admin's ID, IP
+// address or user agent.
+type SecurityActivityResponse struct {
+	ID        int       `json:"id"`
+	Action    string    `json:"action"`
+	Outcome   string    `json:"outcome"`
+	ByAdmin   bool      `json:"by_admin"`
+	IPAddress string    `json:"ip_address,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
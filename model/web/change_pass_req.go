@@ -1,5 +1,10 @@
 package web
 
 type ChangePassReq struct {
-	Password string `json:"password" validate:"required"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+	Password        string `json:"password" validate:"required,password_length,password_bytes,password_classes,password_common,password_identity"`
+	// Username and Email are filled from the current user so the policy
+	// can reject passwords containing them.
+	Username string `json:"-"`
+	Email    string `json:"-"`
 }
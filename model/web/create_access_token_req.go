@@ -0,0 +1,7 @@
+package web
+
+type CreateAccessTokenReq struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=profile:read meals:read meals:write favorites:read favorites:write"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"required,min=1,max=365"`
+}
@@ -0,0 +1,9 @@
+package web
+
+// DeleteAccountReq confirms the deletion with either the current password
+// or a token mailed by POST /api/users/deletion/confirmation, for accounts
+// whose password the user never chose.
+type DeleteAccountReq struct {
+	Password string `json:"password" validate:"required_without=Token"`
+	Token    string `json:"token" validate:"required_without=Password"`
+}
@@ -0,0 +1,5 @@
+package web
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
@@ -0,0 +1,16 @@
+package web
+
+// ListMealsReq is read from the query string of GET /api/meals. The list
+// filters are given by repeating the key, as in category=a&category=b.
+// Cursor is the next_cursor of the previous page.
+type ListMealsReq struct {
+	Name          string   `query:"name" validate:"max=100"`
+	Category      []string `query:"category" validate:"max=20,dive,max=100"`
+	Complexity    []string `query:"complexity" validate:"max=20,dive,max=100"`
+	Affordability []string `query:"affordability" validate:"max=20,dive,max=100"`
+	Diet          []string `query:"diet" validate:"dive,oneof=gluten_free lactose_free vegan"`
+	Sort          string   `query:"sort" validate:"omitempty,oneof=newest name favorites"`
+	Limit         int      `query:"limit" validate:"min=0,max=100"`
+	Cursor        string   `query:"cursor" validate:"max=512"`
+	Include       string   `query:"include" validate:"omitempty,oneof=steps"`
+}
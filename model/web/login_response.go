@@ -0,0 +1,9 @@
+package web
+
+// LoginResponse holds either a token pair or, for users with two-factor
+// authentication, the challenge to answer at /api/login/2fa. Only the set
+// one appears in the JSON.
+type LoginResponse struct {
+	*TokenResponse
+	*TwoFactorChallengeResponse
+}
@@ -14,8 +14,66 @@ type MealResponse struct {
 	IsGlutenFree  bool      `json:"is_gluten_free"`
 	IsLactoseFree bool      `json:"is_lactose_free"`
 	IsVegan       bool      `json:"is_vegan"`
+	IsHidden      bool      `json:"is_hidden"`
 	Ingredients   []string  `json:"ingredients"`
 	Steps         []string  `json:"steps"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
+
+// MealSummaryResponse is a meal as shown in listings. Steps are left out
+// unless the listing asks for them.
+type MealSummaryResponse struct {
+	ID            int       `json:"id"`
+	UserId        int       `json:"user_id"`
+	Name          string    `json:"name"`
+	Category      string    `json:"category"`
+	ImageUrl      string    `json:"image_url"`
+	Duration      string    `json:"duration"`
+	Complexity    string    `json:"complexity"`
+	Affordability string    `json:"affordability"`
+	IsGlutenFree  bool      `json:"is_gluten_free"`
+	IsLactoseFree bool      `json:"is_lactose_free"`
+	IsVegan       bool      `json:"is_vegan"`
+	FavoriteCount int64     `json:"favorite_count"`
+	Ingredients   []string  `json:"ingredients"`
+	Steps         []string  `json:"steps,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CursorMeta describes a page of a cursor paginated listing. NextCursor is
+// null on the last page.
+type CursorMeta struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// MealListMeta is the meta of GET /api/meals. Total counts every meal
+// matching the filters, across all pages.
+type MealListMeta struct {
+	CursorMeta
+	Total  int64      `json:"total"`
+	Facets MealFacets `json:"facets"`
+}
+
+// MealFacets counts the meals per filter value. The counts for a filter
+// apply every other filter, so they tell how many meals selecting that
+// value would show. Diet counts the meals that also have each flag.
+type MealFacets struct {
+	Category      []FacetCount `json:"category"`
+	Complexity    []FacetCount `json:"complexity"`
+	Affordability []FacetCount `json:"affordability"`
+	Diet          DietFacets   `json:"diet"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type DietFacets struct {
+	GlutenFree  int64 `json:"gluten_free"`
+	LactoseFree int64 `json:"lactose_free"`
+	Vegan       int64 `json:"vegan"`
+}
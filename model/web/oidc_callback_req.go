@@ -0,0 +1,7 @@
+package web
+
+type OIDCCallbackReq struct {
+	Code  string `query:"code" validate:"required"`
+	State string `query:"state" validate:"required"`
+	Error string `query:"error"`
+}
@@ -0,0 +1,10 @@
+package web
+
+type ResetPasswordReq struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,password_length,password_bytes,password_classes,password_common,password_identity"`
+	// Username and Email are filled from the account being reset so the
+	// policy can reject passwords containing them.
+	Username string `json:"-"`
+	Email    string `json:"-"`
+}
@@ -0,0 +1,30 @@
+package web
+
+// TwoFactorCodeReq carries a code from the user's authenticator app.
+type TwoFactorCodeReq struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// TwoFactorEnrollReq and TwoFactorConfirmReq ask for the current password,
+// so a stolen access token alone cannot put an authenticator of someone
+// else's on the account.
+type TwoFactorEnrollReq struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+type TwoFactorConfirmReq struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	Code            string `json:"code" validate:"required,max=32"`
+}
+
+// TwoFactorLoginReq finishes a login started by LoginCtrl. Code is either a
+// TOTP code or an unused recovery code.
+type TwoFactorLoginReq struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,max=32"`
+}
+
+type DisableTwoFactorReq struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,max=32"`
+}
@@ -3,5 +3,5 @@ package web
 type UserRegisterReq struct {
 	Username string `json:"username" validate:"required,max=100"`
 	Email    string `json:"email" validate:"required,max=100,email"`
-	Password string `json:"password" validate:"required"`
+	Password string `json:"password" validate:"required,password_length,password_bytes,password_classes,password_common,password_identity"`
 }
@@ -0,0 +1,5 @@
+package web
+
+type VerifyEmailReq struct {
+	Token string `json:"token" query:"token" validate:"required"`
+}
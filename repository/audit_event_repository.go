@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditEventRepository only appends and reads; audit events are never
+// changed or deleted.
+type AuditEventRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, event *entity.AuditEvent) error
+	Search(ctx context.Context, tx *gorm.DB, filter AuditEventFilter) ([]entity.AuditEvent, int64, error)
+}
+
+// AuditEventFilter narrows Search. Zero fields match everything; From and
+// To bound created_at.
+type AuditEventFilter struct {
+	Action     string
+	Outcome    string
+	ActorID    int
+	TargetType string
+	TargetID   int
+	From       time.Time
+	To         time.Time
+	Offset     int
+	Limit      int
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type AuditEventRepositoryImpl struct {
+}
+
+func NewAuditEventRepositoryImpl() AuditEventRepository {
+	return &AuditEventRepositoryImpl{}
+}
+
+func (repository *AuditEventRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, event *entity.AuditEvent) error {
+	return tx.WithContext(ctx).Create(event).Error
+}
+
+// Search returns matching events newest first, with the total count.
+func (repository *AuditEventRepositoryImpl) Search(ctx context.Context, tx *gorm.DB, filter AuditEventFilter) ([]entity.AuditEvent, int64, error) {
+	query := tx.WithContext(ctx).Model(&entity.AuditEvent{})
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var events []entity.AuditEvent
+	err = query.Order("created_at DESC, id DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&events).Error
+	return events, total, err
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginThrottleRepository interface {
+	Reserve(ctx context.Context, tx *gorm.DB, subject string, limit int, now time.Time, since time.Time) (bool, error)
+	Release(ctx context.Context, tx *gorm.DB, subject string) error
+	Lock(ctx context.Context, tx *gorm.DB, subject string, until time.Time) error
+	FindBySubjects(ctx context.Context, tx *gorm.DB, subjects []string) ([]entity.LoginThrottle, error)
+	Delete(ctx context.Context, tx *gorm.DB, subject string) error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginThrottleRepositoryImpl struct {
+}
+
+func NewLoginThrottleRepositoryImpl() LoginThrottleRepository {
+	return &LoginThrottleRepositoryImpl{}
+}
+
+// Reserve counts an attempt for the subject and reports whether it was
+// allowed. It is refused while the subject is locked or once limit
+// attempts were made after since; older attempts are forgotten. The check
+// and the increment are one statement, so concurrent attempts cannot both
+// take the last one.
+func (repository *LoginThrottleRepositoryImpl) Reserve(ctx context.Context, tx *gorm.DB, subject string, limit int, now time.Time, since time.Time) (bool, error) {
+	err := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.LoginThrottle{Subject: subject, LastFailedAt: now}).Error
+	if err != nil {
+		return false, err
+	}
+
+	// Columns are assigned in name order, and MySQL lets later assignments
+	// see earlier ones, so failures must still read the old last_failed_at.
+	result := tx.WithContext(ctx).Model(&entity.LoginThrottle{}).
+		Where("subject = ? AND (locked_until IS NULL OR locked_until <= ?)", subject, now).
+		Where("(failures < ? OR last_failed_at <= ?)", limit, since).
+		Updates(map[string]any{
+			"failures":       gorm.Expr("CASE WHEN last_failed_at <= ? THEN 1 ELSE failures + 1 END", since),
+			"last_failed_at": now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Release gives back an attempt counted by Reserve.
+func (repository *LoginThrottleRepositoryImpl) Release(ctx context.Context, tx *gorm.DB, subject string) error {
+	return tx.WithContext(ctx).Model(&entity.LoginThrottle{}).
+		Where("subject = ? AND failures > 0", subject).
+		Update("failures", gorm.Expr("failures - 1")).Error
+}
+
+// Lock keeps the subject locked until the given time, never shortening a
+// lock that is already in place.
+func (repository *LoginThrottleRepositoryImpl) Lock(ctx context.Context, tx *gorm.DB, subject string, until time.Time) error {
+	return tx.WithContext(ctx).Model(&entity.LoginThrottle{}).
+		Where("subject = ? AND (locked_until IS NULL OR locked_until < ?)", subject, until).
+		Update("locked_until", until).Error
+}
+
+func (repository *LoginThrottleRepositoryImpl) FindBySubjects(ctx context.Context, tx *gorm.DB, subjects []string) ([]entity.LoginThrottle, error) {
+	var throttles []entity.LoginThrottle
+	err := tx.WithContext(ctx).Where("subject IN ?", subjects).Find(&throttles).Error
+	return throttles, err
+}
+
+func (repository *LoginThrottleRepositoryImpl) Delete(ctx context.Context, tx *gorm.DB, subject string) error {
+	return tx.WithContext(ctx).Delete(&entity.LoginThrottle{}, "subject = ?", subject).Error
+}
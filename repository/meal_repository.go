@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type MealRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, meal *entity.MealRecipe) error
+	Update(ctx context.Context, tx *gorm.DB, meal *entity.MealRecipe) error
+	Delete(ctx context.Context, tx *gorm.DB, meal *entity.MealRecipe) error
+	FindByID(ctx context.Context, tx *gorm.DB, mealID int) (entity.MealRecipe, error)
+	List(ctx context.Context, tx *gorm.DB, filter MealFilter) ([]entity.MealRecipe, error)
+	Count(ctx context.Context, tx *gorm.DB, filter MealFilter) (int64, error)
+	CountByColumn(ctx context.Context, tx *gorm.DB, filter MealFilter, column string) ([]ValueCount, error)
+	ReplaceIngredients(ctx context.Context, tx *gorm.DB, mealID int, ingredients []string) error
+	ReplaceSteps(ctx context.Context, tx *gorm.DB, mealID int, steps []string) error
+	AddFavorite(ctx context.Context, tx *gorm.DB, user *entity.User, meal *entity.MealRecipe) error
+	RemoveFavorite(ctx context.Context, tx *gorm.DB, user *entity.User, meal *entity.MealRecipe) error
+	FindFavorite(ctx context.Context, tx *gorm.DB, userID int, mealID int) (entity.MealRecipe, error)
+	FindFavoritesByUser(ctx context.Context, tx *gorm.DB, user *entity.User) ([]entity.MealRecipe, error)
+	FindByUser(ctx context.Context, tx *gorm.DB, userID int) ([]entity.MealRecipe, error)
+	DeleteByUser(ctx context.Context, tx *gorm.DB, userID int) error
+	CountByUser(ctx context.Context, tx *gorm.DB, userID int) (int64, error)
+	TransferOwnership(ctx context.Context, tx *gorm.DB, fromUserID int, toUserID int) error
+}
+
+const (
+	MealSortNewest    = "newest"
+	MealSortName      = "name"
+	MealSortFavorites = "favorites"
+)
+
+// MealFilter narrows and orders List. A meal must match one of the values
+// of each non-empty list, and have every diet flag that is set. Meals come
+// back in a stable order for each sort, so After, the last meal of the
+// previous page, picks up where that page stopped. Steps are only loaded
+// when WithSteps is set.
+type MealFilter struct {
+	Name            string
+	Categories      []string
+	Complexities    []string
+	Affordabilities []string
+	GlutenFree      bool
+	LactoseFree     bool
+	Vegan           bool
+	Sort            string
+	After           *MealCursor
+	Limit           int
+	WithSteps       bool
+}
+
+// MealCursor holds the sort key of the last meal on a page. Only the
+// field used by the sort is read, besides ID.
+type MealCursor struct {
+	ID            int
+	Name          string
+	FavoriteCount int64
+}
+
+// ValueCount is how many meals have a given value in a column.
+type ValueCount struct {
+	Value string
+	Count int64
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type MealRepositoryImpl struct {
+}
+
+func NewMealRepositoryImpl() MealRepository {
+	return &MealRepositoryImpl{}
+}
+
+func (repository *MealRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, meal *entity.MealRecipe) error {
+	return tx.WithContext(ctx).Omit("Ingredients", "Steps").Create(meal).Error
+}
+
+func (repository *MealRepositoryImpl) Update(ctx context.Context, tx *gorm.DB, meal *entity.MealRecipe) error {
+	return tx.WithContext(ctx).Omit("Ingredients", "Steps").Save(meal).Error
+}
+
+func (repository *MealRepositoryImpl) Delete(ctx context.Context, tx *gorm.DB, meal *entity.MealRecipe) error {
+	return tx.WithContext(ctx).Delete(meal).Error
+}
+
+func (repository *MealRepositoryImpl) FindByID(ctx context.Context, tx *gorm.DB, mealID int) (entity.MealRecipe, error) {
+	meal := entity.MealRecipe{}
+	err := tx.WithContext(ctx).Preload("Ingredients").Preload("Steps").Take(&meal, "id = ?", mealID).Error
+	return meal, err
+}
+
+// favoriteCountSQL counts how many users favorited the meal of the
+// current row.
+const favoriteCountSQL = "(SELECT COUNT(*) FROM favorite_user_meal WHERE favorite_user_meal.meal_recipe_id = meal_recipes.id)"
+
+func (repository *MealRepositoryImpl) List(ctx context.Context, tx *gorm.DB, filter MealFilter) ([]entity.MealRecipe, error) {
+	query := filterMeals(tx.WithContext(ctx), filter)
+
+	// Every sort ends on the ID so that meals with the same key keep their
+	// order between pages. IDs are assigned in creation order, which makes
+	// them the key for the newest first sort as well.
+	after := filter.After
+	switch filter.Sort {
+	case MealSortName:
+		if after != nil {
+			query = query.Where("name > ? OR (name = ? AND id > ?)", after.Name, after.Name, after.ID)
+		}
+		query = query.Order("name").Order("id")
+	case MealSortFavorites:
+		if after != nil {
+			query = query.Where(favoriteCountSQL+" < ? OR ("+favoriteCountSQL+" = ? AND id < ?)", after.FavoriteCount, after.FavoriteCount, after.ID)
+		}
+		query = query.Order(favoriteCountSQL + " DESC").Order("id DESC")
+	default:
+		if after != nil {
+			query = query.Where("id < ?", after.ID)
+		}
+		query = query.Order("id DESC")
+	}
+
+	query = query.Preload("Ingredients")
+	if filter.WithSteps {
+		query = query.Preload("Steps")
+	}
+
+	var meals []entity.MealRecipe
+	err := query.Limit(filter.Limit).Find(&meals).Error
+	if err != nil || len(meals) == 0 {
+		return meals, err
+	}
+
+	mealIDs := make([]int, len(meals))
+	for i, meal := range meals {
+		mealIDs[i] = meal.ID
+	}
+
+	var counts []struct {
+		MealRecipeId int
+		Count        int64
+	}
+	err = tx.WithContext(ctx).Table("favorite_user_meal").
+		Select("meal_recipe_id, COUNT(*) AS count").
+		Where("meal_recipe_id IN ?", mealIDs).
+		Group("meal_recipe_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	countByMeal := make(map[int]int64, len(counts))
+	for _, count := range counts {
+		countByMeal[count.MealRecipeId] = count.Count
+	}
+	for i := range meals {
+		meals[i].FavoriteCount = countByMeal[meals[i].ID]
+	}
+	return meals, nil
+}
+
+func (repository *MealRepositoryImpl) Count(ctx context.Context, tx *gorm.DB, filter MealFilter) (int64, error) {
+	var count int64
+	err := filterMeals(tx.WithContext(ctx).Model(&entity.MealRecipe{}), filter).Count(&count).Error
+	return count, err
+}
+
+// CountByColumn counts the meals matching the filter per value of column,
+// most common values first. Column must be a trusted column name.
+func (repository *MealRepositoryImpl) CountByColumn(ctx context.Context, tx *gorm.DB, filter MealFilter, column string) ([]ValueCount, error) {
+	counts := []ValueCount{}
+	err := filterMeals(tx.WithContext(ctx).Model(&entity.MealRecipe{}), filter).
+		Select(column + " AS value, COUNT(*) AS count").
+		Group(column).
+		Order("count DESC").Order("value").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// filterMeals adds the conditions of the filter, leaving out the sort and
+// the cursor, to query.
+func filterMeals(query *gorm.DB, filter MealFilter) *gorm.DB {
+	query = query.Where("hidden_at IS NULL")
+
+	if filter.Name != "" {
+		if query.Dialector.Name() == "mysql" {
+			query = query.Where("MATCH(name) AGAINST (? IN NATURAL LANGUAGE MODE)", filter.Name)
+		} else {
+			// Other dialects have no MATCH ... AGAINST, so fall back to a
+			// case-insensitive substring match on the name.
+			query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+		}
+	}
+	if len(filter.Categories) > 0 {
+		query = query.Where("category IN ?", filter.Categories)
+	}
+	if len(filter.Complexities) > 0 {
+		query = query.Where("complexity IN ?", filter.Complexities)
+	}
+	if len(filter.Affordabilities) > 0 {
+		query = query.Where("affordability IN ?", filter.Affordabilities)
+	}
+	if filter.GlutenFree {
+		query = query.Where("is_gluten_free = ?", true)
+	}
+	if filter.LactoseFree {
+		query = query.Where("is_lactose_free = ?", true)
+	}
+	if filter.Vegan {
+		query = query.Where("is_vegan = ?", true)
+	}
+	return query
+}
+
+func (repository *MealRepositoryImpl) ReplaceIngredients(ctx context.Context, tx *gorm.DB, mealID int, ingredients []string) error {
+	err := tx.WithContext(ctx).Where("meal_recipe_id = ?", mealID).Delete(&entity.MealIngredient{}).Error
+	if err != nil {
+		return err
+	}
+
+	var newIngredients []entity.MealIngredient
+	for _, ingredient := range ingredients {
+		newIngredients = append(newIngredients, entity.MealIngredient{
+			MealRecipeId: mealID,
+			Ingredient:   ingredient,
+		})
+	}
+
+	if len(newIngredients) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).Omit("MealRecipe").Create(&newIngredients).Error
+}
+
+func (repository *MealRepositoryImpl) ReplaceSteps(ctx context.Context, tx *gorm.DB, mealID int, steps []string) error {
+	err := tx.WithContext(ctx).Where("meal_recipe_id = ?", mealID).Delete(&entity.MealRecipeStep{}).Error
+	if err != nil {
+		return err
+	}
+
+	var newSteps []entity.MealRecipeStep
+	for _, step := range steps {
+		newSteps = append(newSteps, entity.MealRecipeStep{
+			MealRecipeId: mealID,
+			Step:         step,
+		})
+	}
+
+	if len(newSteps) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).Omit("MealRecipe").Create(&newSteps).Error
+}
+
+func (repository *MealRepositoryImpl) AddFavorite(ctx context.Context, tx *gorm.DB, user *entity.User, meal *entity.MealRecipe) error {
+	return tx.WithContext(ctx).Model(user).Association("FavoriteMeals").Append(meal)
+}
+
+func (repository *MealRepositoryImpl) RemoveFavorite(ctx context.Context, tx *gorm.DB, user *entity.User, meal *entity.MealRecipe) error {
+	return tx.WithContext(ctx).Model(user).Association("FavoriteMeals").Delete(meal)
+}
+
+func (repository *MealRepositoryImpl) FindFavorite(ctx context.Context, tx *gorm.DB, userID int, mealID int) (entity.MealRecipe, error) {
+	meal := entity.MealRecipe{}
+	err := tx.WithContext(ctx).
+		Joins("JOIN favorite_user_meal ON meal_recipes.id = favorite_user_meal.meal_recipe_id").
+		Where("meal_recipes.id = ? AND favorite_user_meal.user_id = ?", mealID, userID).
+		First(&meal).Error
+	return meal, err
+}
+
+func (repository *MealRepositoryImpl) FindFavoritesByUser(ctx context.Context, tx *gorm.DB, user *entity.User) ([]entity.MealRecipe, error) {
+	var meals []entity.MealRecipe
+	err := tx.WithContext(ctx).Model(user).Where("hidden_at IS NULL").Preload("Ingredients").Preload("Steps").Association("FavoriteMeals").Find(&meals)
+	return meals, err
+}
+
+// FindByUser returns every meal the user owns, hidden ones included.
+func (repository *MealRepositoryImpl) FindByUser(ctx context.Context, tx *gorm.DB, userID int) ([]entity.MealRecipe, error) {
+	var meals []entity.MealRecipe
+	err := tx.WithContext(ctx).Where("user_id = ?", userID).Preload("Ingredients").Preload("Steps").Order("id").Find(&meals).Error
+	return meals, err
+}
+
+func (repository *MealRepositoryImpl) DeleteByUser(ctx context.Context, tx *gorm.DB, userID int) error {
+	return tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.MealRecipe{}).Error
+}
+
+func (repository *MealRepositoryImpl) CountByUser(ctx context.Context, tx *gorm.DB, userID int) (int64, error) {
+	var count int64
+	err := tx.WithContext(ctx).Model(&entity.MealRecipe{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (repository *MealRepositoryImpl) TransferOwnership(ctx context.Context, tx *gorm.DB, fromUserID int, toUserID int) error {
+	return tx.WithContext(ctx).Model(&entity.MealRecipe{}).Where("user_id = ?", fromUserID).Update("user_id", toUserID).Error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type OIDCStateRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, state *entity.OIDCState) error
+	Consume(ctx context.Context, tx *gorm.DB, stateHash string) (entity.OIDCState, error)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type OIDCStateRepositoryImpl struct {
+}
+
+func NewOIDCStateRepositoryImpl() OIDCStateRepository {
+	return &OIDCStateRepositoryImpl{}
+}
+
+func (repository *OIDCStateRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, state *entity.OIDCState) error {
+	return tx.WithContext(ctx).Create(state).Error
+}
+
+// Consume deletes the state and returns it, failing with
+// gorm.ErrRecordNotFound when it does not exist or another request
+// consumed it first.
+func (repository *OIDCStateRepositoryImpl) Consume(ctx context.Context, tx *gorm.DB, stateHash string) (entity.OIDCState, error) {
+	state := entity.OIDCState{}
+	err := tx.WithContext(ctx).Take(&state, "state_hash = ?", stateHash).Error
+	if err != nil {
+		return state, err
+	}
+
+	result := tx.WithContext(ctx).Delete(&entity.OIDCState{}, "state_hash = ?", stateHash)
+	if result.Error != nil {
+		return state, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return state, gorm.ErrRecordNotFound
+	}
+
+	return state, nil
+}
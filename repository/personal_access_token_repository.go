@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, token *entity.PersonalAccessToken) error
+	FindByID(ctx context.Context, tx *gorm.DB, tokenID int) (entity.PersonalAccessToken, error)
+	FindActiveByUser(ctx context.Context, tx *gorm.DB, userID int) ([]entity.PersonalAccessToken, error)
+	Revoke(ctx context.Context, tx *gorm.DB, tokenID int) error
+	RevokeByUser(ctx context.Context, tx *gorm.DB, userID int) error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenRepositoryImpl struct {
+}
+
+func NewPersonalAccessTokenRepositoryImpl() PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepositoryImpl{}
+}
+
+func (repository *PersonalAccessTokenRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, token *entity.PersonalAccessToken) error {
+	return tx.WithContext(ctx).Omit("User").Create(token).Error
+}
+
+func (repository *PersonalAccessTokenRepositoryImpl) FindByID(ctx context.Context, tx *gorm.DB, tokenID int) (entity.PersonalAccessToken, error) {
+	token := entity.PersonalAccessToken{}
+	err := tx.WithContext(ctx).Take(&token, "id = ?", tokenID).Error
+	return token, err
+}
+
+func (repository *PersonalAccessTokenRepositoryImpl) FindActiveByUser(ctx context.Context, tx *gorm.DB, userID int) ([]entity.PersonalAccessToken, error) {
+	var tokens []entity.PersonalAccessToken
+	err := tx.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (repository *PersonalAccessTokenRepositoryImpl) Revoke(ctx context.Context, tx *gorm.DB, tokenID int) error {
+	return tx.WithContext(ctx).Model(&entity.PersonalAccessToken{}).
+		Where("id = ? AND revoked_at IS NULL", tokenID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (repository *PersonalAccessTokenRepositoryImpl) RevokeByUser(ctx context.Context, tx *gorm.DB, userID int) error {
+	return tx.WithContext(ctx).Model(&entity.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository interface {
+	Replace(ctx context.Context, tx *gorm.DB, userID int, codeHashes []string) error
+	Use(ctx context.Context, tx *gorm.DB, userID int, codeHash string) (bool, error)
+	DeleteByUser(ctx context.Context, tx *gorm.DB, userID int) error
+}
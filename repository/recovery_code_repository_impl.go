@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepositoryImpl struct {
+}
+
+func NewRecoveryCodeRepositoryImpl() RecoveryCodeRepository {
+	return &RecoveryCodeRepositoryImpl{}
+}
+
+// Replace deletes the user's recovery codes and stores the given ones.
+func (repository *RecoveryCodeRepositoryImpl) Replace(ctx context.Context, tx *gorm.DB, userID int, codeHashes []string) error {
+	err := repository.DeleteByUser(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]entity.RecoveryCode, len(codeHashes))
+	for i, codeHash := range codeHashes {
+		codes[i] = entity.RecoveryCode{UserId: userID, CodeHash: codeHash}
+	}
+
+	return tx.WithContext(ctx).Omit("User").Create(&codes).Error
+}
+
+// Use consumes an unused recovery code and reports whether there was one.
+func (repository *RecoveryCodeRepositoryImpl) Use(ctx context.Context, tx *gorm.DB, userID int, codeHash string) (bool, error) {
+	result := tx.WithContext(ctx).Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (repository *RecoveryCodeRepositoryImpl) DeleteByUser(ctx context.Context, tx *gorm.DB, userID int) error {
+	return tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error
+}
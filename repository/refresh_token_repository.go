@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, token *entity.RefreshToken) error
+	FindByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.RefreshToken, error)
+	Revoke(ctx context.Context, tx *gorm.DB, tokenID int) (bool, error)
+	RevokeFamily(ctx context.Context, tx *gorm.DB, familyID string) error
+}
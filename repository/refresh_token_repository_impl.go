@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepositoryImpl struct {
+}
+
+func NewRefreshTokenRepositoryImpl() RefreshTokenRepository {
+	return &RefreshTokenRepositoryImpl{}
+}
+
+func (repository *RefreshTokenRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, token *entity.RefreshToken) error {
+	return tx.WithContext(ctx).Omit("User").Create(token).Error
+}
+
+func (repository *RefreshTokenRepositoryImpl) FindByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.RefreshToken, error) {
+	token := entity.RefreshToken{}
+	err := tx.WithContext(ctx).Take(&token, "token_hash = ?", tokenHash).Error
+	return token, err
+}
+
+// Revoke marks the token as used and reports whether this call was the one
+// that revoked it, so two concurrent refreshes cannot both succeed.
+func (repository *RefreshTokenRepositoryImpl) Revoke(ctx context.Context, tx *gorm.DB, tokenID int) (bool, error) {
+	result := tx.WithContext(ctx).Model(&entity.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", tokenID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (repository *RefreshTokenRepositoryImpl) RevokeFamily(ctx context.Context, tx *gorm.DB, familyID string) error {
+	return tx.WithContext(ctx).Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
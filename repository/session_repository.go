@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, session *entity.Session) error
+	Update(ctx context.Context, tx *gorm.DB, session *entity.Session) error
+	FindByID(ctx context.Context, tx *gorm.DB, sessionID string) (entity.Session, error)
+	FindActiveByUser(ctx context.Context, tx *gorm.DB, userID int) ([]entity.Session, error)
+	Revoke(ctx context.Context, tx *gorm.DB, sessionID string) error
+}
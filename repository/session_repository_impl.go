@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepositoryImpl struct {
+}
+
+func NewSessionRepositoryImpl() SessionRepository {
+	return &SessionRepositoryImpl{}
+}
+
+func (repository *SessionRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, session *entity.Session) error {
+	return tx.WithContext(ctx).Omit("User").Create(session).Error
+}
+
+func (repository *SessionRepositoryImpl) Update(ctx context.Context, tx *gorm.DB, session *entity.Session) error {
+	return tx.WithContext(ctx).Omit("User").Save(session).Error
+}
+
+func (repository *SessionRepositoryImpl) FindByID(ctx context.Context, tx *gorm.DB, sessionID string) (entity.Session, error) {
+	session := entity.Session{}
+	err := tx.WithContext(ctx).Take(&session, "id = ?", sessionID).Error
+	return session, err
+}
+
+func (repository *SessionRepositoryImpl) FindActiveByUser(ctx context.Context, tx *gorm.DB, userID int) ([]entity.Session, error) {
+	var sessions []entity.Session
+	err := tx.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (repository *SessionRepositoryImpl) Revoke(ctx context.Context, tx *gorm.DB, sessionID string) error {
+	return tx.WithContext(ctx).Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error
+}
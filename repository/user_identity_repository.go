@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, userIdentity *entity.UserIdentity) error
+	FindByProviderSubject(ctx context.Context, tx *gorm.DB, provider string, subject string) (entity.UserIdentity, error)
+}
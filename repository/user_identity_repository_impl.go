@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepositoryImpl struct {
+}
+
+func NewUserIdentityRepositoryImpl() UserIdentityRepository {
+	return &UserIdentityRepositoryImpl{}
+}
+
+func (repository *UserIdentityRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, userIdentity *entity.UserIdentity) error {
+	return tx.WithContext(ctx).Omit("User").Save(userIdentity).Error
+}
+
+func (repository *UserIdentityRepositoryImpl) FindByProviderSubject(ctx context.Context, tx *gorm.DB, provider string, subject string) (entity.UserIdentity, error) {
+	userIdentity := entity.UserIdentity{}
+	err := tx.WithContext(ctx).Take(&userIdentity, "provider = ? AND subject = ?", provider, subject).Error
+	return userIdentity, err
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, user *entity.User) error
+	Update(ctx context.Context, tx *gorm.DB, user *entity.User, columns ...string) error
+	IncrementTokenVersion(ctx context.Context, tx *gorm.DB, userID int) error
+	FindByID(ctx context.Context, tx *gorm.DB, userID int) (entity.User, error)
+	FindByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, error)
+	Search(ctx context.Context, tx *gorm.DB, filter UserFilter) ([]entity.User, int64, error)
+	FindDueForDeletion(ctx context.Context, tx *gorm.DB, now time.Time) ([]entity.User, error)
+	Delete(ctx context.Context, tx *gorm.DB, user *entity.User) error
+}
+
+// UserFilter narrows Search. Query matches part of the username or email,
+// and Suspended, when set, keeps only suspended or only active accounts.
+type UserFilter struct {
+	Query     string
+	Role      string
+	Suspended *bool
+	Offset    int
+	Limit     int
+}
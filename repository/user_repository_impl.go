@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserRepositoryImpl struct {
+}
+
+func NewUserRepositoryImpl() UserRepository {
+	return &UserRepositoryImpl{}
+}
+
+func (repository *UserRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, user *entity.User) error {
+	return tx.WithContext(ctx).Create(user).Error
+}
+
+// Update writes only the given columns, so a request holding a copy of the
+// user loaded earlier cannot undo changes made since, such as a suspension.
+func (repository *UserRepositoryImpl) Update(ctx context.Context, tx *gorm.DB, user *entity.User, columns ...string) error {
+	return tx.WithContext(ctx).Model(user).Select(columns).Updates(user).Error
+}
+
+// IncrementTokenVersion invalidates every token issued to the user so far.
+// It increments in the database rather than writing a version read
+// earlier, which a concurrent increment could have passed.
+func (repository *UserRepositoryImpl) IncrementTokenVersion(ctx context.Context, tx *gorm.DB, userID int) error {
+	return tx.WithContext(ctx).Model(&entity.User{}).Where("id = ?", userID).
+		Update("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+func (repository *UserRepositoryImpl) FindByID(ctx context.Context, tx *gorm.DB, userID int) (entity.User, error) {
+	user := entity.User{}
+	err := tx.WithContext(ctx).Take(&user, "id = ?", userID).Error
+	return user, err
+}
+
+func (repository *UserRepositoryImpl) FindByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, error) {
+	user := entity.User{}
+	err := tx.WithContext(ctx).Take(&user, "email = ?", email).Error
+	return user, err
+}
+
+func (repository *UserRepositoryImpl) Search(ctx context.Context, tx *gorm.DB, filter UserFilter) ([]entity.User, int64, error) {
+	query := tx.WithContext(ctx).Model(&entity.User{})
+	if filter.Query != "" {
+		pattern := "%" + strings.ToLower(filter.Query) + "%"
+		query = query.Where("LOWER(username) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Suspended != nil {
+		if *filter.Suspended {
+			query = query.Where("suspended_at IS NOT NULL")
+		} else {
+			query = query.Where("suspended_at IS NULL")
+		}
+	}
+
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var users []entity.User
+	err = query.Order("id").Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error
+	return users, total, err
+}
+
+func (repository *UserRepositoryImpl) FindDueForDeletion(ctx context.Context, tx *gorm.DB, now time.Time) ([]entity.User, error) {
+	var users []entity.User
+	err := tx.WithContext(ctx).Where("deletion_scheduled_at <= ?", now).Find(&users).Error
+	return users, err
+}
+
+func (repository *UserRepositoryImpl) Delete(ctx context.Context, tx *gorm.DB, user *entity.User) error {
+	return tx.WithContext(ctx).Delete(user).Error
+}
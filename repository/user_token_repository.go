@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+
+	"gorm.io/gorm"
+)
+
+type UserTokenRepository interface {
+	Save(ctx context.Context, tx *gorm.DB, token *entity.UserToken) error
+	FindByHash(ctx context.Context, tx *gorm.DB, purpose string, tokenHash string) (entity.UserToken, error)
+	MarkUsed(ctx context.Context, tx *gorm.DB, tokenID int) (bool, error)
+	InvalidateByUser(ctx context.Context, tx *gorm.DB, userID int, purpose string) error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserTokenRepositoryImpl struct {
+}
+
+func NewUserTokenRepositoryImpl() UserTokenRepository {
+	return &UserTokenRepositoryImpl{}
+}
+
+func (repository *UserTokenRepositoryImpl) Save(ctx context.Context, tx *gorm.DB, token *entity.UserToken) error {
+	return tx.WithContext(ctx).Omit("User").Create(token).Error
+}
+
+func (repository *UserTokenRepositoryImpl) FindByHash(ctx context.Context, tx *gorm.DB, purpose string, tokenHash string) (entity.UserToken, error) {
+	token := entity.UserToken{}
+	err := tx.WithContext(ctx).Take(&token, "purpose = ? AND token_hash = ?", purpose, tokenHash).Error
+	return token, err
+}
+
+// MarkUsed consumes the token and reports whether this call was the one
+// that consumed it.
+func (repository *UserTokenRepositoryImpl) MarkUsed(ctx context.Context, tx *gorm.DB, tokenID int) (bool, error) {
+	result := tx.WithContext(ctx).Model(&entity.UserToken{}).
+		Where("id = ? AND used_at IS NULL", tokenID).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// InvalidateByUser consumes every outstanding token of the given purpose,
+// so only the most recently mailed one works.
+func (repository *UserTokenRepositoryImpl) InvalidateByUser(ctx context.Context, tx *gorm.DB, userID int, purpose string) error {
+	return tx.WithContext(ctx).Model(&entity.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
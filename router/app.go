@@ -0,0 +1,76 @@
+package router
+
+import (
+	"meals-app/config"
+	"meals-app/controller"
+	"meals-app/exception"
+	"meals-app/mail"
+	"meals-app/repository"
+	"meals-app/service"
+	"meals-app/signing"
+	"meals-app/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"gorm.io/gorm"
+)
+
+// NewApp wires repositories, services and controllers on top of the given
+// database, image store, mailer and JWT key set and returns the Fiber app with every route
+// registered.
+func NewApp(cfg *config.Config, db *gorm.DB, imageStore storage.ImageStore, mailer mail.Mailer, keySet *signing.KeySet) *fiber.App {
+	validate, uni := config.NewValidator(cfg.Password)
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler:            exception.NewFiberErrorHandler(uni),
+		ProxyHeader:             cfg.App.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.App.TrustedProxies,
+		EnableIPValidation:      true,
+	})
+	app.Use(recover.New())
+
+	if localStore, ok := imageStore.(*storage.LocalImageStore); ok {
+		app.Static(localStore.Route, localStore.Dir)
+	}
+
+	userRepository := repository.NewUserRepositoryImpl()
+	mealRepository := repository.NewMealRepositoryImpl()
+	refreshTokenRepository := repository.NewRefreshTokenRepositoryImpl()
+	sessionRepository := repository.NewSessionRepositoryImpl()
+	userTokenRepository := repository.NewUserTokenRepositoryImpl()
+	loginThrottleRepository := repository.NewLoginThrottleRepositoryImpl()
+	userIdentityRepository := repository.NewUserIdentityRepositoryImpl()
+	oidcStateRepository := repository.NewOIDCStateRepositoryImpl()
+	recoveryCodeRepository := repository.NewRecoveryCodeRepositoryImpl()
+	personalAccessTokenRepository := repository.NewPersonalAccessTokenRepositoryImpl()
+	auditEventRepository := repository.NewAuditEventRepositoryImpl()
+
+	tokenService := service.NewTokenServiceImpl(refreshTokenRepository, sessionRepository, userRepository, auditEventRepository, db, validate, cfg.JWT, keySet)
+	loginThrottleService := service.NewLoginThrottleServiceImpl(loginThrottleRepository, userRepository, db, cfg.Login)
+	twoFactorService := service.NewTwoFactorServiceImpl(userRepository, userTokenRepository, recoveryCodeRepository, auditEventRepository, db, validate, tokenService, loginThrottleService, cfg.TwoFactor)
+	userService := service.NewUserServiceImpl(userRepository, mealRepository, userTokenRepository, auditEventRepository, personalAccessTokenRepository, db, validate, imageStore, twoFactorService, loginThrottleService, mailer, cfg)
+	mealService := service.NewMealServiceImpl(mealRepository, auditEventRepository, db, validate, imageStore)
+	sessionService := service.NewSessionServiceImpl(sessionRepository, refreshTokenRepository, db)
+	accessTokenService := service.NewAccessTokenServiceImpl(personalAccessTokenRepository, db, validate)
+	accountService := service.NewAccountServiceImpl(userRepository, userTokenRepository, mealRepository, loginThrottleRepository, db, validate, mailer, cfg.Account)
+	auditService := service.NewAuditServiceImpl(auditEventRepository, db, validate)
+	adminService := service.NewAdminServiceImpl(userRepository, userTokenRepository, auditEventRepository, personalAccessTokenRepository, db, validate, mailer, cfg.Account)
+	oidcService := service.NewOIDCServiceImpl(config.NewIdentityProviders(cfg), oidcStateRepository, userIdentityRepository, userRepository, db, validate, twoFactorService, cfg.OIDC)
+
+	userController := controller.NewUserControllerImpl(userService, tokenService)
+	mealController := controller.NewMealControllerImpl(mealService)
+	sessionController := controller.NewSessionControllerImpl(sessionService)
+	adminController := controller.NewAdminControllerImpl(adminService, loginThrottleService)
+	healthController := controller.NewHealthControllerImpl(db, imageStore)
+	keyController := controller.NewKeyControllerImpl(keySet)
+	oidcController := controller.NewOIDCControllerImpl(oidcService)
+	twoFactorController := controller.NewTwoFactorControllerImpl(twoFactorService)
+	accessTokenController := controller.NewAccessTokenControllerImpl(accessTokenService)
+	accountController := controller.NewAccountControllerImpl(accountService)
+	auditController := controller.NewAuditControllerImpl(auditService)
+
+	SetupRouter(app, db, keySet, userController, mealController, sessionController, adminController, healthController, keyController, oidcController, twoFactorController, accessTokenController, accountController, auditController)
+
+	return app
+}
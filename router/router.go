@@ -3,30 +3,77 @@ package router
 import (
 	"meals-app/controller"
 	"meals-app/middleware"
+	"meals-app/model/entity"
+	"meals-app/signing"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
-func SetupRouter(app *fiber.App, db *gorm.DB, userCtrl controller.UserController, mealCtrl controller.MealController) {
+func SetupRouter(app *fiber.App, db *gorm.DB, keySet *signing.KeySet, userCtrl controller.UserController, mealCtrl controller.MealController, sessionCtrl controller.SessionController, adminCtrl controller.AdminController, healthCtrl controller.HealthController, keyCtrl controller.KeyController, oidcCtrl controller.OIDCController, twoFactorCtrl controller.TwoFactorController, accessTokenCtrl controller.AccessTokenController, accountCtrl controller.AccountController, auditCtrl controller.AuditController) {
+	app.Get("/healthz", healthCtrl.LivenessCtrl)
+	app.Get("/readyz", healthCtrl.ReadinessCtrl)
+	app.Get("/.well-known/jwks.json", keyCtrl.JWKSCtrl)
+
+	protected := middleware.Protected(db, keySet)
+	// scoped routes also accept personal access tokens with the scope.
+	scoped := func(scope string) fiber.Handler {
+		return middleware.Protected(db, keySet, scope)
+	}
+
 	api := app.Group("/api")
 	api.Post("/register", userCtrl.RegisterCtrl)
+	api.Get("/verify-email", userCtrl.VerifyEmailCtrl)
+	api.Post("/password/forgot", userCtrl.ForgotPasswordCtrl)
+	api.Post("/password/reset", userCtrl.ResetPasswordCtrl)
 	api.Post("/login", userCtrl.LoginCtrl)
+	api.Post("/login/2fa", twoFactorCtrl.VerifyCtrl)
+	api.Post("/token/refresh", userCtrl.RefreshTokenCtrl)
+	api.Get("/oidc/:provider/login", oidcCtrl.LoginCtrl)
+	api.Get("/oidc/:provider/callback", oidcCtrl.CallbackCtrl)
+	api.Post("/logout", protected, sessionCtrl.LogoutCtrl)
 
 	user := api.Group("/users")
-	user.Get("/", middleware.Protected(db), userCtrl.ProfileCtrl)
-	user.Put("/", middleware.Protected(db), userCtrl.UpdateProfileCtrl)
-	user.Put("/change-password", middleware.Protected(db), userCtrl.UpdatePasswordCtrl)
-	user.Put("/image", middleware.Protected(db), userCtrl.UpdateImgCtrl)
-	user.Get("/favorites", middleware.Protected(db), userCtrl.GetAllFavoriteCtrl)
+	user.Get("/", scoped(entity.ScopeProfileRead), userCtrl.ProfileCtrl)
+	user.Put("/", protected, userCtrl.UpdateProfileCtrl)
+	user.Post("/verify-email", protected, userCtrl.ResendVerificationCtrl)
+	user.Put("/change-password", protected, userCtrl.UpdatePasswordCtrl)
+	user.Put("/image", protected, userCtrl.UpdateImgCtrl)
+	user.Get("/favorites", scoped(entity.ScopeFavoritesRead), userCtrl.GetAllFavoriteCtrl)
+	user.Post("/2fa/enroll", protected, twoFactorCtrl.EnrollCtrl)
+	user.Post("/2fa/confirm", protected, twoFactorCtrl.ConfirmCtrl)
+	user.Post("/2fa/recovery-codes", protected, twoFactorCtrl.RegenerateRecoveryCodesCtrl)
+	user.Delete("/2fa", protected, twoFactorCtrl.DisableCtrl)
+	user.Get("/sessions", protected, sessionCtrl.GetAllSessionCtrl)
+	user.Delete("/sessions/:id", protected, sessionCtrl.DeleteSessionCtrl)
+	user.Post("/tokens", protected, accessTokenCtrl.CreateAccessTokenCtrl)
+	user.Get("/tokens", protected, accessTokenCtrl.GetAllAccessTokenCtrl)
+	user.Delete("/tokens/:id", protected, accessTokenCtrl.DeleteAccessTokenCtrl)
+	user.Get("/security-activity", protected, auditCtrl.GetSecurityActivityCtrl)
+	user.Get("/export", protected, accountCtrl.ExportCtrl)
+	user.Post("/deletion/confirmation", protected, accountCtrl.RequestDeletionTokenCtrl)
+	user.Post("/deletion", protected, accountCtrl.DeleteAccountCtrl)
+	user.Delete("/deletion", protected, accountCtrl.CancelDeletionCtrl)
 
 	meal := api.Group("/meals")
-	meal.Post("/", middleware.Protected(db), mealCtrl.CreateMealCtrl)
-	meal.Get("/", middleware.Protected(db), mealCtrl.GetAllMealCtrl)
-	meal.Get("/:id", middleware.Protected(db), mealCtrl.GetMealByIDCtrl)
-	meal.Put("/:id", middleware.Protected(db), mealCtrl.UpdateMealCtrl)
-	meal.Put("/:id/image", middleware.Protected(db), mealCtrl.UpdateMealImageCtrl)
-	meal.Delete("/:id", middleware.Protected(db), mealCtrl.DeleteMealCtrl)
-	meal.Post("/:id/favorites", middleware.Protected(db), mealCtrl.AddToFavoriteCtrl)
-	meal.Delete("/:id/favorites", middleware.Protected(db), mealCtrl.DeleteFromFavoriteCtrl)
+	meal.Post("/", scoped(entity.ScopeMealsWrite), mealCtrl.CreateMealCtrl)
+	meal.Get("/", scoped(entity.ScopeMealsRead), mealCtrl.GetAllMealCtrl)
+	meal.Get("/:id", scoped(entity.ScopeMealsRead), mealCtrl.GetMealByIDCtrl)
+	meal.Put("/:id", scoped(entity.ScopeMealsWrite), mealCtrl.UpdateMealCtrl)
+	meal.Put("/:id/image", scoped(entity.ScopeMealsWrite), mealCtrl.UpdateMealImageCtrl)
+	meal.Delete("/:id", scoped(entity.ScopeMealsWrite), mealCtrl.DeleteMealCtrl)
+	meal.Post("/:id/hide", protected, middleware.RequirePermission(entity.PermissionHideMeal), mealCtrl.HideMealCtrl)
+	meal.Delete("/:id/hide", protected, middleware.RequirePermission(entity.PermissionHideMeal), mealCtrl.UnhideMealCtrl)
+	meal.Post("/:id/favorites", scoped(entity.ScopeFavoritesWrite), mealCtrl.AddToFavoriteCtrl)
+	meal.Delete("/:id/favorites", scoped(entity.ScopeFavoritesWrite), mealCtrl.DeleteFromFavoriteCtrl)
+
+	admin := api.Group("/admin", protected, middleware.RequirePermission(entity.PermissionManageUsers))
+	admin.Get("/users", adminCtrl.GetAllUserCtrl)
+	admin.Get("/users/:id", adminCtrl.GetUserByIDCtrl)
+	admin.Put("/users/:id/role", adminCtrl.UpdateRoleCtrl)
+	admin.Post("/users/:id/suspend", adminCtrl.SuspendUserCtrl)
+	admin.Delete("/users/:id/suspend", adminCtrl.UnsuspendUserCtrl)
+	admin.Post("/users/:id/password-reset", adminCtrl.ForcePasswordResetCtrl)
+	admin.Post("/users/:id/unlock", adminCtrl.UnlockUserCtrl)
+	admin.Get("/audit-events", auditCtrl.GetAllAuditEventCtrl)
 }
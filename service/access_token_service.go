@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+type AccessTokenService interface {
+	Create(ctx context.Context, user entity.User, request web.CreateAccessTokenReq) (web.AccessTokenResponse, error)
+	FindAll(ctx context.Context, user entity.User) ([]web.AccessTokenResponse, error)
+	Revoke(ctx context.Context, user entity.User, tokenID int) error
+}
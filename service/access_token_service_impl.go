@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type AccessTokenServiceImpl struct {
+	PersonalAccessTokenRepository repository.PersonalAccessTokenRepository
+	DB                            *gorm.DB
+	Validate                      *validator.Validate
+}
+
+func NewAccessTokenServiceImpl(personalAccessTokenRepository repository.PersonalAccessTokenRepository, DB *gorm.DB, validate *validator.Validate) AccessTokenService {
+	return &AccessTokenServiceImpl{
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+		DB:                            DB,
+		Validate:                      validate,
+	}
+}
+
+// Create issues a personal access token. The secret is returned only here;
+// afterwards the token can be listed and revoked but not read back.
+func (service *AccessTokenServiceImpl) Create(ctx context.Context, user entity.User, request web.CreateAccessTokenReq) (web.AccessTokenResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.AccessTokenResponse{}, exception.NewValidationError(err)
+	}
+
+	secret, err := helper.GenerateToken()
+	if err != nil {
+		return web.AccessTokenResponse{}, err
+	}
+	secret = entity.AccessTokenPrefix + secret
+
+	token := entity.PersonalAccessToken{
+		UserId:    user.ID,
+		Name:      request.Name,
+		TokenHash: helper.HashToken(secret),
+		Scopes:    entity.JoinScopes(request.Scopes),
+		ExpiresAt: time.Now().AddDate(0, 0, request.ExpiresInDays),
+	}
+
+	err = service.PersonalAccessTokenRepository.Save(ctx, service.DB, &token)
+	if err != nil {
+		return web.AccessTokenResponse{}, err
+	}
+
+	response := helper.ToAccessTokenResponse(token)
+	response.Token = secret
+	return response, nil
+}
+
+func (service *AccessTokenServiceImpl) FindAll(ctx context.Context, user entity.User) ([]web.AccessTokenResponse, error) {
+	tokens, err := service.PersonalAccessTokenRepository.FindActiveByUser(ctx, service.DB, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []web.AccessTokenResponse{}
+	for _, token := range tokens {
+		responses = append(responses, helper.ToAccessTokenResponse(token))
+	}
+
+	return responses, nil
+}
+
+func (service *AccessTokenServiceImpl) Revoke(ctx context.Context, user entity.User, tokenID int) error {
+	token, err := service.PersonalAccessTokenRepository.FindByID(ctx, service.DB, tokenID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.ErrAccessTokenNotFound
+		}
+		return err
+	}
+
+	if token.UserId != user.ID || token.RevokedAt != nil {
+		return exception.ErrAccessTokenNotFound
+	}
+
+	return service.PersonalAccessTokenRepository.Revoke(ctx, service.DB, token.ID)
+}
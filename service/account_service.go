@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+type AccountService interface {
+	Export(ctx context.Context, user entity.User) (web.AccountExport, error)
+	RequestDeletionToken(ctx context.Context, user entity.User) error
+	ScheduleDeletion(ctx context.Context, user entity.User, request web.DeleteAccountReq) (web.AccountDeletionResponse, error)
+	CancelDeletion(ctx context.Context, user entity.User) error
+	PurgeDeleted(ctx context.Context) (int, error)
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"meals-app/config"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/mail"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AccountServiceImpl struct {
+	UserRepository          repository.UserRepository
+	UserTokenRepository     repository.UserTokenRepository
+	MealRepository          repository.MealRepository
+	LoginThrottleRepository repository.LoginThrottleRepository
+	DB                      *gorm.DB
+	Validate                *validator.Validate
+	Mailer                  mail.Mailer
+	AccountConfig           config.AccountConfig
+}
+
+func NewAccountServiceImpl(userRepository repository.UserRepository, userTokenRepository repository.UserTokenRepository, mealRepository repository.MealRepository, loginThrottleRepository repository.LoginThrottleRepository, DB *gorm.DB, validate *validator.Validate, mailer mail.Mailer, accountConfig config.AccountConfig) AccountService {
+	return &AccountServiceImpl{
+		UserRepository:          userRepository,
+		UserTokenRepository:     userTokenRepository,
+		MealRepository:          mealRepository,
+		LoginThrottleRepository: loginThrottleRepository,
+		DB:                      DB,
+		Validate:                validate,
+		Mailer:                  mailer,
+		AccountConfig:           accountConfig,
+	}
+}
+
+func (service *AccountServiceImpl) Export(ctx context.Context, user entity.User) (web.AccountExport, error) {
+	recipes, err := service.MealRepository.FindByUser(ctx, service.DB, user.ID)
+	if err != nil {
+		return web.AccountExport{}, err
+	}
+
+	favorites, err := service.MealRepository.FindFavoritesByUser(ctx, service.DB, &user)
+	if err != nil {
+		return web.AccountExport{}, err
+	}
+
+	export := web.AccountExport{
+		ExportedAt: time.Now(),
+		Profile:    helper.ToUserResponse(user),
+		Recipes:    []web.MealResponse{},
+		Favorites:  []web.MealResponse{},
+	}
+	for _, recipe := range recipes {
+		export.Recipes = append(export.Recipes, helper.ToMealResponse(recipe))
+	}
+	for _, favorite := range favorites {
+		export.Favorites = append(export.Favorites, helper.ToMealResponse(favorite))
+	}
+
+	return export, nil
+}
+
+// RequestDeletionToken mails a token that confirms the deletion in place
+// of the password. Accounts created through an identity provider have a
+// random password, so this is the only way for them to delete themselves.
+func (service *AccountServiceImpl) RequestDeletionToken(ctx context.Context, user entity.User) error {
+	if user.DeletionScheduledAt != nil {
+		return exception.ErrDeletionScheduled
+	}
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		secret, err := issueUserToken(ctx, tx, service.UserTokenRepository, user.ID, entity.TokenPurposeAccountDeletion, service.AccountConfig.DeletionTokenExpiry)
+		if err != nil {
+			return err
+		}
+
+		return service.Mailer.Send(ctx, mail.Message{
+			To:      user.Email,
+			Subject: "Confirm your account deletion",
+			Body: fmt.Sprintf("Hi %s,\n\nUse the token below to confirm that you want to delete your account:\n\n%s\n\nIf you did not ask for this, you can ignore this email.\n",
+				user.Username, secret),
+		})
+	})
+}
+
+// ScheduleDeletion marks the account for deletion once the grace period
+// has passed, after checking the password or a mailed deletion token.
+// Until then the user can still log in and cancel.
+func (service *AccountServiceImpl) ScheduleDeletion(ctx context.Context, user entity.User, request web.DeleteAccountReq) (web.AccountDeletionResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.AccountDeletionResponse{}, exception.NewValidationError(err)
+	}
+
+	if user.DeletionScheduledAt != nil {
+		return web.AccountDeletionResponse{}, exception.ErrDeletionScheduled
+	}
+
+	if request.Password != "" && !helper.VerifyPassword(request.Password, user.Password) {
+		return web.AccountDeletionResponse{}, exception.ErrWrongPassword
+	}
+
+	deleteAt := time.Now().Add(service.AccountConfig.DeletionGracePeriod)
+	user.DeletionScheduledAt = &deleteAt
+
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		if request.Password == "" {
+			token, err := consumeUserToken(ctx, tx, service.UserTokenRepository, entity.TokenPurposeAccountDeletion, request.Token, exception.ErrInvalidDeletionToken)
+			if err != nil {
+				return err
+			}
+			if token.UserId != user.ID {
+				return exception.ErrInvalidDeletionToken
+			}
+		}
+
+		err := service.UserRepository.Update(ctx, tx, &user, "deletion_scheduled_at")
+		if err != nil {
+			return err
+		}
+
+		return service.Mailer.Send(ctx, mail.Message{
+			To:      user.Email,
+			Subject: "Your account will be deleted",
+			Body: fmt.Sprintf("Hi %s,\n\nYour account and its data will be deleted on %s. To keep your account, log in and cancel the deletion before then.\n",
+				user.Username, deleteAt.UTC().Format(time.RFC1123)),
+		})
+	})
+	if err != nil {
+		return web.AccountDeletionResponse{}, err
+	}
+
+	return web.AccountDeletionResponse{DeletionScheduledAt: deleteAt}, nil
+}
+
+func (service *AccountServiceImpl) CancelDeletion(ctx context.Context, user entity.User) error {
+	if user.DeletionScheduledAt == nil {
+		return exception.ErrDeletionNotScheduled
+	}
+
+	user.DeletionScheduledAt = nil
+	return service.UserRepository.Update(ctx, service.DB, &user, "deletion_scheduled_at")
+}
+
+// PurgeDeleted deletes every account whose grace period has passed and
+// returns how many were deleted.
+func (service *AccountServiceImpl) PurgeDeleted(ctx context.Context) (int, error) {
+	users, err := service.UserRepository.FindDueForDeletion(ctx, service.DB, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	for i, user := range users {
+		err = service.DB.Transaction(func(tx *gorm.DB) error {
+			return service.delete(ctx, tx, user)
+		})
+		if err != nil {
+			return i, fmt.Errorf("delete user %d: %w", user.ID, err)
+		}
+	}
+
+	return len(users), nil
+}
+
+// delete removes the user. Their sessions, tokens, identities and
+// favorites go with the row; recipes do not cascade, so they are deleted
+// or handed to an anonymous placeholder user first, depending on
+// DeletedRecipes.
+func (service *AccountServiceImpl) delete(ctx context.Context, tx *gorm.DB, user entity.User) error {
+	count, err := service.MealRepository.CountByUser(ctx, tx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 && service.AccountConfig.DeletedRecipes == "anonymize" {
+		placeholder, err := service.newPlaceholderUser(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		err = service.MealRepository.TransferOwnership(ctx, tx, user.ID, placeholder.ID)
+		if err != nil {
+			return err
+		}
+	} else if count > 0 {
+		err = service.MealRepository.DeleteByUser(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = service.LoginThrottleRepository.Delete(ctx, tx, emailSubject(user.Email))
+	if err != nil {
+		return err
+	}
+
+	return service.UserRepository.Delete(ctx, tx, &user)
+}
+
+// newPlaceholderUser creates the owner for a deleted user's recipes. It has
+// no real email address or usable password, so nobody can log in as it.
+func (service *AccountServiceImpl) newPlaceholderUser(ctx context.Context, tx *gorm.DB) (entity.User, error) {
+	user := entity.User{
+		Username: "deleted user",
+		Email:    "deleted-" + uuid.NewString() + "@users.invalid",
+		Role:     entity.RoleUser,
+		ImageUrl: defaultImageUrl,
+	}
+
+	err := service.UserRepository.Save(ctx, tx, &user)
+	return user, err
+}
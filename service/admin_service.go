@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+type AdminService interface {
+	FindUsers(ctx context.Context, request web.SearchUsersReq) ([]web.AdminUserResponse, web.PageMeta, error)
+	FindUserByID(ctx context.Context, userID int) (web.AdminUserResponse, error)
+	UpdateRole(ctx context.Context, admin entity.User, userID int, request web.UpdateRoleReq, client web.ClientInfo) (web.AdminUserResponse, error)
+	Suspend(ctx context.Context, admin entity.User, userID int, request web.SuspendUserReq, client web.ClientInfo) (web.AdminUserResponse, error)
+	Unsuspend(ctx context.Context, admin entity.User, userID int, client web.ClientInfo) (web.AdminUserResponse, error)
+	ForcePasswordReset(ctx context.Context, admin entity.User, userID int, client web.ClientInfo) (web.AdminUserResponse, error)
+}
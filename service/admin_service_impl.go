@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"meals-app/config"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/mail"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const defaultPerPage = 20
+
+type AdminServiceImpl struct {
+	UserRepository                repository.UserRepository
+	UserTokenRepository           repository.UserTokenRepository
+	AuditEventRepository          repository.AuditEventRepository
+	PersonalAccessTokenRepository repository.PersonalAccessTokenRepository
+	DB                            *gorm.DB
+	Validate                      *validator.Validate
+	Mailer                        mail.Mailer
+	AccountConfig                 config.AccountConfig
+}
+
+func NewAdminServiceImpl(userRepository repository.UserRepository, userTokenRepository repository.UserTokenRepository, auditEventRepository repository.AuditEventRepository, personalAccessTokenRepository repository.PersonalAccessTokenRepository, DB *gorm.DB, validate *validator.Validate, mailer mail.Mailer, accountConfig config.AccountConfig) AdminService {
+	return &AdminServiceImpl{
+		UserRepository:                userRepository,
+		UserTokenRepository:           userTokenRepository,
+		AuditEventRepository:          auditEventRepository,
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+		DB:                            DB,
+		Validate:                      validate,
+		Mailer:                        mailer,
+		AccountConfig:                 accountConfig,
+	}
+}
+
+func (service *AdminServiceImpl) FindUsers(ctx context.Context, request web.SearchUsersReq) ([]web.AdminUserResponse, web.PageMeta, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return nil, web.PageMeta{}, exception.NewValidationError(err)
+	}
+
+	meta := newPageMeta(request.Page, request.PerPage)
+
+	filter := repository.UserFilter{
+		Query:  request.Query,
+		Role:   request.Role,
+		Offset: (meta.Page - 1) * meta.PerPage,
+		Limit:  meta.PerPage,
+	}
+	if request.Status != "" {
+		suspended := request.Status == "suspended"
+		filter.Suspended = &suspended
+	}
+
+	users, total, err := service.UserRepository.Search(ctx, service.DB, filter)
+	if err != nil {
+		return nil, web.PageMeta{}, err
+	}
+	meta.Total = total
+
+	responses := []web.AdminUserResponse{}
+	for _, user := range users {
+		responses = append(responses, helper.ToAdminUserResponse(user))
+	}
+
+	return responses, meta, nil
+}
+
+func (service *AdminServiceImpl) FindUserByID(ctx context.Context, userID int) (web.AdminUserResponse, error) {
+	user, err := service.findUser(ctx, service.DB, userID)
+	if err != nil {
+		return web.AdminUserResponse{}, err
+	}
+
+	return helper.ToAdminUserResponse(user), nil
+}
+
+// UpdateRole changes a user's role. Admins cannot change their own, so the
+// last admin cannot lock everyone out of user management by accident.
+func (service *AdminServiceImpl) UpdateRole(ctx context.Context, admin entity.User, userID int, request web.UpdateRoleReq, client web.ClientInfo) (web.AdminUserResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.AdminUserResponse{}, exception.NewValidationError(err)
+	}
+
+	if admin.ID == userID {
+		return web.AdminUserResponse{}, exception.ErrManageSelf
+	}
+
+	event := userEvent(entity.AuditRoleChange, admin.ID, userID)
+	return service.updateUser(ctx, userID, event, client, []string{"role"}, func(tx *gorm.DB, user *entity.User, event *entity.AuditEvent) error {
+		event.Detail = user.Role + " to " + request.Role
+		user.Role = request.Role
+		return nil
+	})
+}
+
+// Suspend blocks the user from logging in and from every API call. Bumping
+// the token version ends their sessions; personal access tokens stay but
+// are refused while the account is suspended.
+func (service *AdminServiceImpl) Suspend(ctx context.Context, admin entity.User, userID int, request web.SuspendUserReq, client web.ClientInfo) (web.AdminUserResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.AdminUserResponse{}, exception.NewValidationError(err)
+	}
+
+	if admin.ID == userID {
+		return web.AdminUserResponse{}, exception.ErrManageSelf
+	}
+
+	event := userEvent(entity.AuditSuspend, admin.ID, userID)
+	event.Detail = request.Reason
+	return service.updateUser(ctx, userID, event, client, []string{"suspended_at", "suspension_reason"}, func(tx *gorm.DB, user *entity.User, event *entity.AuditEvent) error {
+		if user.Suspended() {
+			return exception.ErrAlreadySuspended
+		}
+
+		now := time.Now()
+		user.SuspendedAt = &now
+		user.SuspensionReason = request.Reason
+		return service.UserRepository.IncrementTokenVersion(ctx, tx, user.ID)
+	})
+}
+
+func (service *AdminServiceImpl) Unsuspend(ctx context.Context, admin entity.User, userID int, client web.ClientInfo) (web.AdminUserResponse, error) {
+	event := userEvent(entity.AuditUnsuspend, admin.ID, userID)
+	return service.updateUser(ctx, userID, event, client, []string{"suspended_at", "suspension_reason"}, func(tx *gorm.DB, user *entity.User, event *entity.AuditEvent) error {
+		if !user.Suspended() {
+			return exception.ErrNotSuspended
+		}
+
+		user.SuspendedAt = nil
+		user.SuspensionReason = ""
+		return nil
+	})
+}
+
+// ForcePasswordReset logs the user out everywhere, revokes their personal
+// access tokens and refuses password logins until they choose a new
+// password through the emailed reset token.
+func (service *AdminServiceImpl) ForcePasswordReset(ctx context.Context, admin entity.User, userID int, client web.ClientInfo) (web.AdminUserResponse, error) {
+	event := userEvent(entity.AuditForcePasswordReset, admin.ID, userID)
+	return service.updateUser(ctx, userID, event, client, []string{"password_reset_required"}, func(tx *gorm.DB, user *entity.User, event *entity.AuditEvent) error {
+		user.PasswordResetRequired = true
+		err := service.UserRepository.IncrementTokenVersion(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = service.PersonalAccessTokenRepository.RevokeByUser(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
+		return sendPasswordReset(ctx, tx, service.UserTokenRepository, service.Mailer, service.AccountConfig, *user,
+			"An administrator requires you to choose a new password before you can log in again.")
+	})
+}
+
+// updateUser applies change to the user, saves the given columns and
+// records event in one transaction. change may fill in the event's detail.
+func (service *AdminServiceImpl) updateUser(ctx context.Context, userID int, event entity.AuditEvent, client web.ClientInfo, columns []string, change func(tx *gorm.DB, user *entity.User, event *entity.AuditEvent) error) (web.AdminUserResponse, error) {
+	var user entity.User
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		user, err = service.findUser(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		err = change(tx, &user, &event)
+		if err != nil {
+			return err
+		}
+
+		err = service.UserRepository.Update(ctx, tx, &user, columns...)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, tx, service.AuditEventRepository, client, event)
+	})
+	if err != nil {
+		return web.AdminUserResponse{}, err
+	}
+
+	return helper.ToAdminUserResponse(user), nil
+}
+
+// newPageMeta fills in the first page and the default page size for
+// parameters left out of the query string.
+func newPageMeta(page int, perPage int) web.PageMeta {
+	if perPage == 0 {
+		perPage = defaultPerPage
+	}
+	return web.PageMeta{Page: max(page, 1), PerPage: perPage}
+}
+
+func (service *AdminServiceImpl) findUser(ctx context.Context, tx *gorm.DB, userID int) (entity.User, error) {
+	user, err := service.UserRepository.FindByID(ctx, tx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.User{}, exception.ErrUserNotFound
+		}
+		return entity.User{}, err
+	}
+	return user, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+
+	"gorm.io/gorm"
+)
+
+// userEvent starts a successful audit event on the account targetID by
+// actorID. Either may be 0 when unknown, such as the actor of a failed
+// login.
+func userEvent(action string, actorID int, targetID int) entity.AuditEvent {
+	return auditEvent(action, actorID, entity.AuditTargetUser, targetID)
+}
+
+func mealEvent(action string, actorID int, mealID int) entity.AuditEvent {
+	return auditEvent(action, actorID, entity.AuditTargetMeal, mealID)
+}
+
+func auditEvent(action string, actorID int, targetType string, targetID int) entity.AuditEvent {
+	event := entity.AuditEvent{Action: action, Outcome: entity.AuditSuccess}
+	if actorID != 0 {
+		event.ActorID = &actorID
+	}
+	if targetID != 0 {
+		event.TargetType = targetType
+		event.TargetID = &targetID
+	}
+	return event
+}
+
+// recordAuditEvent appends event to the audit log with the IP address and
+// user agent of the client that caused it. Successful changes record it in
+// their own transaction; failures pass the plain DB after rolling back.
+func recordAuditEvent(ctx context.Context, tx *gorm.DB, auditEventRepository repository.AuditEventRepository, client web.ClientInfo, event entity.AuditEvent) error {
+	event.IPAddress = truncate(client.IPAddress, 45)
+	event.UserAgent = truncate(client.UserAgent, 255)
+	event.Detail = truncate(event.Detail, 255)
+	return auditEventRepository.Save(ctx, tx, &event)
+}
+
+// loginFailed records a refused login and returns cause. user is the zero
+// User when the email is unknown, in which case the email is kept instead.
+func loginFailed(ctx context.Context, db *gorm.DB, auditEventRepository repository.AuditEventRepository, client web.ClientInfo, user entity.User, email string, cause error) error {
+	event := userEvent(entity.AuditLogin, 0, user.ID)
+	event.Outcome = entity.AuditFailure
+	event.Detail = cause.Error()
+	if user.ID == 0 {
+		event.Detail += " for " + email
+	}
+
+	err := recordAuditEvent(ctx, db, auditEventRepository, client, event)
+	if err != nil {
+		return err
+	}
+	return cause
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+type AuditService interface {
+	FindAll(ctx context.Context, request web.SearchAuditEventsReq) ([]web.AuditEventResponse, web.PageMeta, error)
+	FindRecentByUser(ctx context.Context, user entity.User) ([]web.SecurityActivityResponse, error)
+}
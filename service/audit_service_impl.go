@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// recentActivityLimit is how many events the security activity view shows.
+const recentActivityLimit = 20
+
+type AuditServiceImpl struct {
+	AuditEventRepository repository.AuditEventRepository
+	DB                   *gorm.DB
+	Validate             *validator.Validate
+}
+
+func NewAuditServiceImpl(auditEventRepository repository.AuditEventRepository, DB *gorm.DB, validate *validator.Validate) AuditService {
+	return &AuditServiceImpl{
+		AuditEventRepository: auditEventRepository,
+		DB:                   DB,
+		Validate:             validate,
+	}
+}
+
+func (service *AuditServiceImpl) FindAll(ctx context.Context, request web.SearchAuditEventsReq) ([]web.AuditEventResponse, web.PageMeta, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return nil, web.PageMeta{}, exception.NewValidationError(err)
+	}
+
+	meta := newPageMeta(request.Page, request.PerPage)
+	filter := repository.AuditEventFilter{
+		Action:     request.Action,
+		Outcome:    request.Outcome,
+		ActorID:    request.ActorID,
+		TargetType: request.TargetType,
+		TargetID:   request.TargetID,
+		Offset:     (meta.Page - 1) * meta.PerPage,
+		Limit:      meta.PerPage,
+	}
+	// The validator already checked the format.
+	if request.From != "" {
+		filter.From, _ = time.Parse(time.RFC3339, request.From)
+	}
+	if request.To != "" {
+		filter.To, _ = time.Parse(time.RFC3339, request.To)
+	}
+
+	events, total, err := service.AuditEventRepository.Search(ctx, service.DB, filter)
+	if err != nil {
+		return nil, web.PageMeta{}, err
+	}
+	meta.Total = total
+
+	return helper.ToAuditEventResponses(events), meta, nil
+}
+
+// FindRecentByUser lists the latest events on the user's account, such as
+// logins and password changes, including those made by admins.
+func (service *AuditServiceImpl) FindRecentByUser(ctx context.Context, user entity.User) ([]web.SecurityActivityResponse, error) {
+	events, _, err := service.AuditEventRepository.Search(ctx, service.DB, repository.AuditEventFilter{
+		TargetType: entity.AuditTargetUser,
+		TargetID:   user.ID,
+		Limit:      recentActivityLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.ToSecurityActivityResponses(events, user.ID), nil
+}
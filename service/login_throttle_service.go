@@ -0,0 +1,10 @@
+package service
+
+import "context"
+
+type LoginThrottleService interface {
+	Reserve(ctx context.Context, email string, ipAddress string) error
+	RecordFailure(ctx context.Context, email string, ipAddress string) error
+	Reset(ctx context.Context, email string, ipAddress string) error
+	Unlock(ctx context.Context, userID int) error
+}
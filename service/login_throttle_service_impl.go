@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"meals-app/config"
+	"meals-app/exception"
+	"meals-app/repository"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginThrottleServiceImpl struct {
+	LoginThrottleRepository repository.LoginThrottleRepository
+	UserRepository          repository.UserRepository
+	DB                      *gorm.DB
+	LoginConfig             config.LoginConfig
+}
+
+func NewLoginThrottleServiceImpl(loginThrottleRepository repository.LoginThrottleRepository, userRepository repository.UserRepository, DB *gorm.DB, loginConfig config.LoginConfig) LoginThrottleService {
+	return &LoginThrottleServiceImpl{
+		LoginThrottleRepository: loginThrottleRepository,
+		UserRepository:          userRepository,
+		DB:                      DB,
+		LoginConfig:             loginConfig,
+	}
+}
+
+// Reserve counts a login attempt against the email and the IP address
+// before the password is checked, so concurrent guesses cannot all get in
+// ahead of the lockout. It fails while either is locked out or has no
+// attempts left. The email does not have to belong to an account, so the
+// answer does not reveal who is registered.
+func (service *LoginThrottleServiceImpl) Reserve(ctx context.Context, email string, ipAddress string) error {
+	now := time.Now()
+	since := now.Add(-service.LoginConfig.LockoutDuration)
+	maxAttempts := service.maxAttempts(email, ipAddress)
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		for _, subject := range []string{emailSubject(email), ipSubject(ipAddress)} {
+			reserved, err := service.LoginThrottleRepository.Reserve(ctx, tx, subject, maxAttempts[subject], now, since)
+			if err != nil {
+				return err
+			}
+			if !reserved {
+				return service.tooManyAttempts(ctx, tx, subject, now)
+			}
+		}
+
+		return nil
+	})
+}
+
+// RecordFailure delays the next attempt after a reserved one failed.
+func (service *LoginThrottleServiceImpl) RecordFailure(ctx context.Context, email string, ipAddress string) error {
+	maxAttempts := service.maxAttempts(email, ipAddress)
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		throttles, err := service.LoginThrottleRepository.FindBySubjects(ctx, tx, []string{emailSubject(email), ipSubject(ipAddress)})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, throttle := range throttles {
+			delay := service.delay(throttle.Failures, maxAttempts[throttle.Subject])
+			if delay == 0 {
+				continue
+			}
+
+			err = service.LoginThrottleRepository.Lock(ctx, tx, throttle.Subject, now.Add(delay))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Reset forgets the failed attempts for an email after a successful login
+// and gives back the attempt reserved for the IP address. The IP address
+// keeps its earlier failures so one valid account cannot be used to keep
+// guessing others.
+func (service *LoginThrottleServiceImpl) Reset(ctx context.Context, email string, ipAddress string) error {
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.LoginThrottleRepository.Delete(ctx, tx, emailSubject(email))
+		if err != nil {
+			return err
+		}
+
+		return service.LoginThrottleRepository.Release(ctx, tx, ipSubject(ipAddress))
+	})
+}
+
+func (service *LoginThrottleServiceImpl) Unlock(ctx context.Context, userID int) error {
+	user, err := service.UserRepository.FindByID(ctx, service.DB, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.ErrUserNotFound
+		}
+		return err
+	}
+
+	return service.LoginThrottleRepository.Delete(ctx, service.DB, emailSubject(user.Email))
+}
+
+func (service *LoginThrottleServiceImpl) maxAttempts(email string, ipAddress string) map[string]int {
+	return map[string]int{
+		emailSubject(email):  service.LoginConfig.MaxAttempts,
+		ipSubject(ipAddress): service.LoginConfig.IPMaxAttempts,
+	}
+}
+
+// tooManyAttempts reports a refused reservation. A subject that is not
+// locked yet has attempts in flight and is free again once they are
+// forgotten.
+func (service *LoginThrottleServiceImpl) tooManyAttempts(ctx context.Context, tx *gorm.DB, subject string, now time.Time) error {
+	throttles, err := service.LoginThrottleRepository.FindBySubjects(ctx, tx, []string{subject})
+	if err != nil {
+		return err
+	}
+
+	retryAfter := service.LoginConfig.LockoutDuration
+	for _, throttle := range throttles {
+		if throttle.Locked(now) {
+			retryAfter = throttle.LockedUntil.Sub(now)
+		} else {
+			retryAfter = throttle.LastFailedAt.Add(service.LoginConfig.LockoutDuration).Sub(now)
+		}
+	}
+
+	return exception.NewTooManyRequestsError("too many failed login attempts, try again later", max(retryAfter, time.Second))
+}
+
+// delay doubles BaseDelay with every failure, capped at LockoutDuration,
+// which also applies once the failures reach limit.
+func (service *LoginThrottleServiceImpl) delay(failures int, limit int) time.Duration {
+	lockout := service.LoginConfig.LockoutDuration
+	if failures >= limit {
+		return lockout
+	}
+
+	delay := service.LoginConfig.BaseDelay
+	for i := 1; i < failures && delay < lockout; i++ {
+		delay *= 2
+	}
+	return min(delay, lockout)
+}
+
+func emailSubject(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipSubject(ipAddress string) string {
+	return "ip:" + ipAddress
+}
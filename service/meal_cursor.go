@@ -0,0 +1,52 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"meals-app/exception"
+	"meals-app/model/entity"
+	"meals-app/repository"
+)
+
+// mealCursor is the position after which the next page of meals starts. It
+// records the sort it was made for, since the keys mean nothing under
+// another order.
+type mealCursor struct {
+	Sort          string `json:"s"`
+	ID            int    `json:"i"`
+	Name          string `json:"n,omitempty"`
+	FavoriteCount int64  `json:"f,omitempty"`
+}
+
+func encodeMealCursor(sort string, meal entity.MealRecipe) string {
+	cursor := mealCursor{Sort: sort, ID: meal.ID}
+	switch sort {
+	case repository.MealSortName:
+		cursor.Name = meal.Name
+	case repository.MealSortFavorites:
+		cursor.FavoriteCount = meal.FavoriteCount
+	}
+
+	// Marshalling a struct of strings and numbers cannot fail.
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeMealCursor(sort string, value string) (*repository.MealCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, exception.ErrInvalidCursor
+	}
+
+	var cursor mealCursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || cursor.Sort != sort || cursor.ID <= 0 {
+		return nil, exception.ErrInvalidCursor
+	}
+
+	return &repository.MealCursor{
+		ID:            cursor.ID,
+		Name:          cursor.Name,
+		FavoriteCount: cursor.FavoriteCount,
+	}, nil
+}
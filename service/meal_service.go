@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"io"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+type MealService interface {
+	Create(ctx context.Context, user entity.User, request web.CreateMealReq, file io.Reader, filename string) (web.MealResponse, error)
+	FindAll(ctx context.Context, request web.ListMealsReq) ([]web.MealSummaryResponse, web.MealListMeta, error)
+	FindByID(ctx context.Context, user entity.User, mealID int) (web.MealResponse, error)
+	Update(ctx context.Context, user entity.User, mealID int, request web.UpdateMealReq) (web.MealResponse, error)
+	UpdateImage(ctx context.Context, user entity.User, mealID int, file io.Reader, filename string) (web.MealResponse, error)
+	Delete(ctx context.Context, user entity.User, mealID int, client web.ClientInfo) error
+	SetHidden(ctx context.Context, mealID int, hidden bool) (web.MealResponse, error)
+	AddToFavorite(ctx context.Context, user entity.User, mealID int) (web.MealResponse, error)
+	DeleteFromFavorite(ctx context.Context, user entity.User, mealID int) error
+}
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+	"meals-app/storage"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type MealServiceImpl struct {
+	MealRepository       repository.MealRepository
+	AuditEventRepository repository.AuditEventRepository
+	DB                   *gorm.DB
+	Validate             *validator.Validate
+	ImageStore           storage.ImageStore
+}
+
+func NewMealServiceImpl(mealRepository repository.MealRepository, auditEventRepository repository.AuditEventRepository, DB *gorm.DB, validate *validator.Validate, imageStore storage.ImageStore) MealService {
+	return &MealServiceImpl{
+		MealRepository:       mealRepository,
+		AuditEventRepository: auditEventRepository,
+		DB:                   DB,
+		Validate:             validate,
+		ImageStore:           imageStore,
+	}
+}
+
+func (service *MealServiceImpl) Create(ctx context.Context, user entity.User, request web.CreateMealReq, file io.Reader, filename string) (web.MealResponse, error) {
+	if !user.IsVerified {
+		return web.MealResponse{}, exception.ErrEmailNotVerified
+	}
+
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.MealResponse{}, exception.NewValidationError(err)
+	}
+
+	isGlutenFree, err := strconv.ParseBool(request.IsGlutenFree)
+	if err != nil {
+		return web.MealResponse{}, exception.NewBadInputError("is_gluten_free must be a boolean")
+	}
+	isLactoseFree, err := strconv.ParseBool(request.IsLactoseFree)
+	if err != nil {
+		return web.MealResponse{}, exception.NewBadInputError("is_lactose_free must be a boolean")
+	}
+	isVegan, err := strconv.ParseBool(request.IsVegan)
+	if err != nil {
+		return web.MealResponse{}, exception.NewBadInputError("is_vegan must be a boolean")
+	}
+
+	imageUrl, err := service.ImageStore.Upload(ctx, file, filename)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	var meal entity.MealRecipe
+
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		meal = entity.MealRecipe{
+			UserId:        user.ID,
+			Name:          request.Name,
+			Category:      request.Category,
+			ImageUrl:      imageUrl,
+			Duration:      request.Duration,
+			Complexity:    request.Complexity,
+			Affordability: request.Affordability,
+			IsGlutenFree:  isGlutenFree,
+			IsLactoseFree: isLactoseFree,
+			IsVegan:       isVegan,
+		}
+
+		err := service.MealRepository.Save(ctx, tx, &meal)
+		if err != nil {
+			return err
+		}
+
+		err = service.MealRepository.ReplaceIngredients(ctx, tx, meal.ID, request.Ingredients)
+		if err != nil {
+			return err
+		}
+
+		err = service.MealRepository.ReplaceSteps(ctx, tx, meal.ID, request.Steps)
+		if err != nil {
+			return err
+		}
+
+		meal, err = service.MealRepository.FindByID(ctx, tx, meal.ID)
+		return err
+	})
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	return helper.ToMealResponse(meal), nil
+}
+
+func (service *MealServiceImpl) FindAll(ctx context.Context, request web.ListMealsReq) ([]web.MealSummaryResponse, web.MealListMeta, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return nil, web.MealListMeta{}, exception.NewValidationError(err)
+	}
+
+	meta := web.MealListMeta{CursorMeta: web.CursorMeta{Limit: request.Limit}}
+	if meta.Limit == 0 {
+		meta.Limit = defaultPerPage
+	}
+
+	filter := repository.MealFilter{
+		Name:            request.Name,
+		Categories:      request.Category,
+		Complexities:    request.Complexity,
+		Affordabilities: request.Affordability,
+		GlutenFree:      slices.Contains(request.Diet, "gluten_free"),
+		LactoseFree:     slices.Contains(request.Diet, "lactose_free"),
+		Vegan:           slices.Contains(request.Diet, "vegan"),
+		Sort:            request.Sort,
+		Limit:           meta.Limit + 1,
+		WithSteps:       request.Include == "steps",
+	}
+	if filter.Sort == "" {
+		filter.Sort = repository.MealSortNewest
+	}
+	if request.Cursor != "" {
+		filter.After, err = decodeMealCursor(filter.Sort, request.Cursor)
+		if err != nil {
+			return nil, web.MealListMeta{}, err
+		}
+	}
+
+	// One meal more than the page holds tells whether another page follows.
+	meals, err := service.MealRepository.List(ctx, service.DB, filter)
+	if err != nil {
+		return nil, web.MealListMeta{}, err
+	}
+	if len(meals) > meta.Limit {
+		meals = meals[:meta.Limit]
+		next := encodeMealCursor(filter.Sort, meals[len(meals)-1])
+		meta.NextCursor = &next
+	}
+
+	meta.Total, err = service.MealRepository.Count(ctx, service.DB, filter)
+	if err != nil {
+		return nil, web.MealListMeta{}, err
+	}
+	meta.Facets, err = service.countFacets(ctx, filter)
+	if err != nil {
+		return nil, web.MealListMeta{}, err
+	}
+
+	return helper.ToMealSummaryResponses(meals), meta, nil
+}
+
+// countFacets counts the meals for each value of a filter with that filter
+// left out, and for each diet flag with the flag added.
+func (service *MealServiceImpl) countFacets(ctx context.Context, filter repository.MealFilter) (web.MealFacets, error) {
+	var facets web.MealFacets
+	var err error
+
+	columns := []struct {
+		column string
+		facet  *[]web.FacetCount
+		clear  func(filter *repository.MealFilter)
+	}{
+		{"category", &facets.Category, func(filter *repository.MealFilter) { filter.Categories = nil }},
+		{"complexity", &facets.Complexity, func(filter *repository.MealFilter) { filter.Complexities = nil }},
+		{"affordability", &facets.Affordability, func(filter *repository.MealFilter) { filter.Affordabilities = nil }},
+	}
+	for _, column := range columns {
+		columnFilter := filter
+		column.clear(&columnFilter)
+
+		counts, err := service.MealRepository.CountByColumn(ctx, service.DB, columnFilter, column.column)
+		if err != nil {
+			return web.MealFacets{}, err
+		}
+
+		*column.facet = []web.FacetCount{}
+		for _, count := range counts {
+			*column.facet = append(*column.facet, web.FacetCount{Value: count.Value, Count: count.Count})
+		}
+	}
+
+	diets := []struct {
+		count *int64
+		set   func(filter *repository.MealFilter)
+	}{
+		{&facets.Diet.GlutenFree, func(filter *repository.MealFilter) { filter.GlutenFree = true }},
+		{&facets.Diet.LactoseFree, func(filter *repository.MealFilter) { filter.LactoseFree = true }},
+		{&facets.Diet.Vegan, func(filter *repository.MealFilter) { filter.Vegan = true }},
+	}
+	for _, diet := range diets {
+		dietFilter := filter
+		diet.set(&dietFilter)
+
+		*diet.count, err = service.MealRepository.Count(ctx, service.DB, dietFilter)
+		if err != nil {
+			return web.MealFacets{}, err
+		}
+	}
+
+	return facets, nil
+}
+
+func (service *MealServiceImpl) FindByID(ctx context.Context, user entity.User, mealID int) (web.MealResponse, error) {
+	meal, err := service.findVisibleMeal(ctx, user, mealID)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	return helper.ToMealResponse(meal), nil
+}
+
+func (service *MealServiceImpl) Update(ctx context.Context, user entity.User, mealID int, request web.UpdateMealReq) (web.MealResponse, error) {
+	meal, err := service.findManagedMeal(ctx, user, mealID, entity.PermissionUpdateAnyMeal)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	err = service.Validate.Struct(request)
+	if err != nil {
+		return web.MealResponse{}, exception.NewValidationError(err)
+	}
+
+	if request.Name != "" {
+		meal.Name = request.Name
+	}
+
+	if request.Category != "" {
+		meal.Category = request.Category
+	}
+
+	if request.Duration != "" {
+		meal.Duration = request.Duration
+	}
+
+	if request.Complexity != "" {
+		meal.Complexity = request.Complexity
+	}
+
+	if request.Affordability != "" {
+		meal.Affordability = request.Affordability
+	}
+
+	if request.IsGlutenFree != "" {
+		isGlutenFree, err := strconv.ParseBool(request.IsGlutenFree)
+		if err != nil {
+			return web.MealResponse{}, exception.NewBadInputError("is_gluten_free must be a boolean")
+		}
+		meal.IsGlutenFree = isGlutenFree
+	}
+
+	if request.IsLactoseFree != "" {
+		isLactoseFree, err := strconv.ParseBool(request.IsLactoseFree)
+		if err != nil {
+			return web.MealResponse{}, exception.NewBadInputError("is_lactose_free must be a boolean")
+		}
+		meal.IsLactoseFree = isLactoseFree
+	}
+
+	if request.IsVegan != "" {
+		isVegan, err := strconv.ParseBool(request.IsVegan)
+		if err != nil {
+			return web.MealResponse{}, exception.NewBadInputError("is_vegan must be a boolean")
+		}
+		meal.IsVegan = isVegan
+	}
+
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.MealRepository.Update(ctx, tx, &meal)
+		if err != nil {
+			return err
+		}
+
+		if len(request.Ingredients) > 0 {
+			err = service.MealRepository.ReplaceIngredients(ctx, tx, meal.ID, request.Ingredients)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(request.Steps) > 0 {
+			err = service.MealRepository.ReplaceSteps(ctx, tx, meal.ID, request.Steps)
+			if err != nil {
+				return err
+			}
+		}
+
+		meal, err = service.MealRepository.FindByID(ctx, tx, meal.ID)
+		return err
+	})
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	return helper.ToMealResponse(meal), nil
+}
+
+func (service *MealServiceImpl) UpdateImage(ctx context.Context, user entity.User, mealID int, file io.Reader, filename string) (web.MealResponse, error) {
+	meal, err := service.findManagedMeal(ctx, user, mealID, entity.PermissionUpdateAnyMeal)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	imageUrl, err := service.ImageStore.Upload(ctx, file, filename)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	meal.ImageUrl = imageUrl
+
+	err = service.MealRepository.Update(ctx, service.DB, &meal)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	return helper.ToMealResponse(meal), nil
+}
+
+// Delete removes a meal. Deleting someone else's meal is recorded in the
+// audit log.
+func (service *MealServiceImpl) Delete(ctx context.Context, user entity.User, mealID int, client web.ClientInfo) error {
+	meal, err := service.findManagedMeal(ctx, user, mealID, entity.PermissionDeleteAnyMeal)
+	if err != nil {
+		return err
+	}
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.MealRepository.Delete(ctx, tx, &meal)
+		if err != nil || meal.UserId == user.ID {
+			return err
+		}
+
+		event := mealEvent(entity.AuditMealDelete, user.ID, meal.ID)
+		event.Detail = fmt.Sprintf("%q owned by user %d", meal.Name, meal.UserId)
+		return recordAuditEvent(ctx, tx, service.AuditEventRepository, client, event)
+	})
+}
+
+// SetHidden hides a meal from listings and from everyone but its owner
+// and moderators, or makes it visible again.
+func (service *MealServiceImpl) SetHidden(ctx context.Context, mealID int, hidden bool) (web.MealResponse, error) {
+	meal, err := service.findMeal(ctx, service.DB, mealID)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	if hidden && meal.HiddenAt == nil {
+		now := time.Now()
+		meal.HiddenAt = &now
+	} else if !hidden {
+		meal.HiddenAt = nil
+	}
+
+	err = service.MealRepository.Update(ctx, service.DB, &meal)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	return helper.ToMealResponse(meal), nil
+}
+
+func (service *MealServiceImpl) AddToFavorite(ctx context.Context, user entity.User, mealID int) (web.MealResponse, error) {
+	meal, err := service.findVisibleMeal(ctx, user, mealID)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	err = service.MealRepository.AddFavorite(ctx, service.DB, &user, &meal)
+	if err != nil {
+		return web.MealResponse{}, err
+	}
+
+	return helper.ToMealResponse(meal), nil
+}
+
+func (service *MealServiceImpl) DeleteFromFavorite(ctx context.Context, user entity.User, mealID int) error {
+	meal, err := service.MealRepository.FindFavorite(ctx, service.DB, user.ID, mealID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.ErrFavoriteNotFound
+		}
+		return err
+	}
+
+	return service.MealRepository.RemoveFavorite(ctx, service.DB, &user, &meal)
+}
+
+func (service *MealServiceImpl) findMeal(ctx context.Context, tx *gorm.DB, mealID int) (entity.MealRecipe, error) {
+	meal, err := service.MealRepository.FindByID(ctx, tx, mealID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return meal, exception.ErrMealNotFound
+		}
+		return meal, err
+	}
+
+	return meal, nil
+}
+
+// findVisibleMeal hides hidden meals from everyone but their owner and
+// users allowed to hide meals.
+func (service *MealServiceImpl) findVisibleMeal(ctx context.Context, user entity.User, mealID int) (entity.MealRecipe, error) {
+	meal, err := service.findMeal(ctx, service.DB, mealID)
+	if err != nil {
+		return meal, err
+	}
+
+	if meal.HiddenAt != nil && meal.UserId != user.ID && !user.Can(entity.PermissionHideMeal) {
+		return meal, exception.ErrMealNotFound
+	}
+
+	return meal, nil
+}
+
+// findManagedMeal loads a meal the user owns, or any meal when their role
+// grants permission.
+func (service *MealServiceImpl) findManagedMeal(ctx context.Context, user entity.User, mealID int, permission string) (entity.MealRecipe, error) {
+	meal, err := service.findVisibleMeal(ctx, user, mealID)
+	if err != nil {
+		return meal, err
+	}
+
+	if meal.UserId != user.ID && !user.Can(permission) {
+		return meal, exception.ErrForbidden
+	}
+
+	return meal, nil
+}
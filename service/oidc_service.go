@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+	"meals-app/model/web"
+)
+
+type OIDCService interface {
+	AuthURL(ctx context.Context, provider string) (string, error)
+	Callback(ctx context.Context, provider string, request web.OIDCCallbackReq, client web.ClientInfo) (web.LoginResponse, error)
+}
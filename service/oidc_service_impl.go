@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"meals-app/config"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/identity"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+type OIDCServiceImpl struct {
+	Providers              map[string]identity.Provider
+	OIDCStateRepository    repository.OIDCStateRepository
+	UserIdentityRepository repository.UserIdentityRepository
+	UserRepository         repository.UserRepository
+	DB                     *gorm.DB
+	Validate               *validator.Validate
+	TwoFactorService       TwoFactorService
+	OIDCConfig             config.OIDCConfig
+}
+
+func NewOIDCServiceImpl(providers map[string]identity.Provider, oidcStateRepository repository.OIDCStateRepository, userIdentityRepository repository.UserIdentityRepository, userRepository repository.UserRepository, DB *gorm.DB, validate *validator.Validate, twoFactorService TwoFactorService, oidcConfig config.OIDCConfig) OIDCService {
+	return &OIDCServiceImpl{
+		Providers:              providers,
+		OIDCStateRepository:    oidcStateRepository,
+		UserIdentityRepository: userIdentityRepository,
+		UserRepository:         userRepository,
+		DB:                     DB,
+		Validate:               validate,
+		TwoFactorService:       twoFactorService,
+		OIDCConfig:             oidcConfig,
+	}
+}
+
+// AuthURL starts an authorization code flow with PKCE and returns the
+// provider URL to send the user to.
+func (service *OIDCServiceImpl) AuthURL(ctx context.Context, providerName string) (string, error) {
+	provider, ok := service.Providers[providerName]
+	if !ok {
+		return "", exception.ErrProviderNotFound
+	}
+
+	state, err := helper.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := helper.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	codeVerifier := oauth2.GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return "", err
+	}
+
+	err = service.OIDCStateRepository.Save(ctx, service.DB, &entity.OIDCState{
+		StateHash:    helper.HashToken(state),
+		Provider:     providerName,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(service.OIDCConfig.StateExpiry),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// Callback finishes the flow and logs in the user holding the provider
+// account. Unknown accounts are linked to the user with the same verified
+// email, or to a new user when there is none.
+func (service *OIDCServiceImpl) Callback(ctx context.Context, providerName string, request web.OIDCCallbackReq, client web.ClientInfo) (web.LoginResponse, error) {
+	provider, ok := service.Providers[providerName]
+	if !ok {
+		return web.LoginResponse{}, exception.ErrProviderNotFound
+	}
+
+	if request.Error != "" {
+		return web.LoginResponse{}, exception.NewUnauthorizedError("identity provider login failed: " + request.Error)
+	}
+
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.LoginResponse{}, exception.NewValidationError(err)
+	}
+
+	state, err := service.OIDCStateRepository.Consume(ctx, service.DB, helper.HashToken(request.State))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return web.LoginResponse{}, exception.ErrInvalidOIDCState
+		}
+		return web.LoginResponse{}, err
+	}
+	if state.Provider != providerName || time.Now().After(state.ExpiresAt) {
+		return web.LoginResponse{}, exception.ErrInvalidOIDCState
+	}
+
+	claims, err := provider.Exchange(ctx, request.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf("oidc %s: %v", providerName, err)
+		return web.LoginResponse{}, exception.ErrOIDCLoginFailed
+	}
+
+	var user entity.User
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		user, err = service.linkUser(ctx, tx, providerName, claims)
+		return err
+	})
+	if err != nil {
+		return web.LoginResponse{}, err
+	}
+
+	return service.TwoFactorService.Login(ctx, user, client)
+}
+
+func (service *OIDCServiceImpl) linkUser(ctx context.Context, tx *gorm.DB, providerName string, claims identity.Claims) (entity.User, error) {
+	userIdentity, err := service.UserIdentityRepository.FindByProviderSubject(ctx, tx, providerName, claims.Subject)
+	if err == nil {
+		return service.UserRepository.FindByID(ctx, tx, userIdentity.UserId)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.User{}, err
+	}
+
+	// Linking by email is only safe when the provider vouches for it.
+	if claims.Email == "" || !claims.EmailVerified {
+		return entity.User{}, exception.ErrOIDCEmailNotVerified
+	}
+
+	// An unverified account may have been registered by someone else ahead
+	// of the owner of the address, with a password they know, so it is
+	// never linked. A password reset proves the address and verifies it.
+	user, err := service.UserRepository.FindByEmail(ctx, tx, claims.Email)
+	switch {
+	case err == nil:
+		if !user.IsVerified {
+			return entity.User{}, exception.ErrUnverifiedAccount
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		user, err = service.newUser(ctx, tx, claims)
+	}
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	err = service.UserIdentityRepository.Save(ctx, tx, &entity.UserIdentity{
+		UserId:   user.ID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+// newUser creates an account for a provider login. Its password is random,
+// so it can only log in through the provider until the user resets it.
+func (service *OIDCServiceImpl) newUser(ctx context.Context, tx *gorm.DB, claims identity.Claims) (entity.User, error) {
+	secret, err := helper.GenerateToken()
+	if err != nil {
+		return entity.User{}, err
+	}
+	hashedPassword, err := helper.HashPassword(secret)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	username := claims.Name
+	if username == "" {
+		username, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	user := entity.User{
+		Username:   truncate(username, 100),
+		Email:      claims.Email,
+		Password:   hashedPassword,
+		ImageUrl:   defaultImageUrl,
+		IsVerified: true,
+	}
+
+	err = service.UserRepository.Save(ctx, tx, &user)
+	return user, err
+}
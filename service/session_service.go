@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+type SessionService interface {
+	FindAll(ctx context.Context, user entity.User, currentSessionID string) ([]web.SessionResponse, error)
+	Revoke(ctx context.Context, user entity.User, sessionID string) error
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"meals-app/exception"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+
+	"gorm.io/gorm"
+)
+
+type SessionServiceImpl struct {
+	SessionRepository      repository.SessionRepository
+	RefreshTokenRepository repository.RefreshTokenRepository
+	DB                     *gorm.DB
+}
+
+func NewSessionServiceImpl(sessionRepository repository.SessionRepository, refreshTokenRepository repository.RefreshTokenRepository, DB *gorm.DB) SessionService {
+	return &SessionServiceImpl{
+		SessionRepository:      sessionRepository,
+		RefreshTokenRepository: refreshTokenRepository,
+		DB:                     DB,
+	}
+}
+
+func (service *SessionServiceImpl) FindAll(ctx context.Context, user entity.User, currentSessionID string) ([]web.SessionResponse, error) {
+	sessions, err := service.SessionRepository.FindActiveByUser(ctx, service.DB, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []web.SessionResponse{}
+	for _, session := range sessions {
+		responses = append(responses, web.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			Current:    session.ID == currentSessionID,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			CreatedAt:  session.CreatedAt,
+		})
+	}
+
+	return responses, nil
+}
+
+// Revoke ends one of the user's sessions. Access tokens carrying its jti
+// stop working immediately and its refresh tokens can no longer be used.
+func (service *SessionServiceImpl) Revoke(ctx context.Context, user entity.User, sessionID string) error {
+	session, err := service.SessionRepository.FindByID(ctx, service.DB, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.ErrSessionNotFound
+		}
+		return err
+	}
+
+	if session.UserId != user.ID {
+		return exception.ErrSessionNotFound
+	}
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.SessionRepository.Revoke(ctx, tx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.RefreshTokenRepository.RevokeFamily(ctx, tx, session.ID)
+	})
+}
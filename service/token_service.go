@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+type TokenService interface {
+	Issue(ctx context.Context, user entity.User, client web.ClientInfo) (web.TokenResponse, error)
+	Refresh(ctx context.Context, request web.RefreshTokenReq, client web.ClientInfo) (web.TokenResponse, error)
+}
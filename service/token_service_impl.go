@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"meals-app/config"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+	"meals-app/signing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TokenServiceImpl struct {
+	RefreshTokenRepository repository.RefreshTokenRepository
+	SessionRepository      repository.SessionRepository
+	UserRepository         repository.UserRepository
+	AuditEventRepository   repository.AuditEventRepository
+	DB                     *gorm.DB
+	Validate               *validator.Validate
+	JWTConfig              config.JWTConfig
+	KeySet                 *signing.KeySet
+}
+
+func NewTokenServiceImpl(refreshTokenRepository repository.RefreshTokenRepository, sessionRepository repository.SessionRepository, userRepository repository.UserRepository, auditEventRepository repository.AuditEventRepository, DB *gorm.DB, validate *validator.Validate, jwtConfig config.JWTConfig, keySet *signing.KeySet) TokenService {
+	return &TokenServiceImpl{
+		RefreshTokenRepository: refreshTokenRepository,
+		SessionRepository:      sessionRepository,
+		UserRepository:         userRepository,
+		AuditEventRepository:   auditEventRepository,
+		DB:                     DB,
+		Validate:               validate,
+		JWTConfig:              jwtConfig,
+		KeySet:                 keySet,
+	}
+}
+
+// Issue opens a new session for the user, as on login. The session ID is
+// both the jti of every access token and the refresh token family.
+func (service *TokenServiceImpl) Issue(ctx context.Context, user entity.User, client web.ClientInfo) (web.TokenResponse, error) {
+	if user.Suspended() {
+		return web.TokenResponse{}, exception.ErrAccountSuspended
+	}
+	if user.PasswordResetRequired {
+		return web.TokenResponse{}, exception.ErrPasswordResetNeeded
+	}
+
+	now := time.Now()
+	session := entity.Session{
+		ID:         uuid.NewString(),
+		UserId:     user.ID,
+		UserAgent:  truncate(client.UserAgent, 255),
+		IPAddress:  client.IPAddress,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(service.JWTConfig.RefreshExpiry),
+	}
+
+	var response web.TokenResponse
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.SessionRepository.Save(ctx, tx, &session)
+		if err != nil {
+			return err
+		}
+
+		response, err = service.issue(ctx, tx, user, session.ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, tx, service.AuditEventRepository, client, userEvent(entity.AuditLogin, user.ID, user.ID))
+	})
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	return response, nil
+}
+
+// Refresh rotates a refresh token. Presenting a token that was already
+// rotated means it leaked, so the whole family is revoked.
+func (service *TokenServiceImpl) Refresh(ctx context.Context, request web.RefreshTokenReq, client web.ClientInfo) (web.TokenResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.TokenResponse{}, exception.NewValidationError(err)
+	}
+
+	token, err := service.RefreshTokenRepository.FindByHash(ctx, service.DB, helper.HashToken(request.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return web.TokenResponse{}, exception.ErrInvalidRefreshToken
+		}
+		return web.TokenResponse{}, err
+	}
+
+	if token.RevokedAt != nil {
+		return web.TokenResponse{}, service.revokeFamily(ctx, token.FamilyID)
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return web.TokenResponse{}, exception.ErrInvalidRefreshToken
+	}
+
+	user, err := service.UserRepository.FindByID(ctx, service.DB, token.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return web.TokenResponse{}, exception.ErrInvalidRefreshToken
+		}
+		return web.TokenResponse{}, err
+	}
+
+	if token.TokenVersion != user.TokenVersion {
+		return web.TokenResponse{}, exception.ErrInvalidRefreshToken
+	}
+
+	if user.Suspended() {
+		return web.TokenResponse{}, exception.ErrAccountSuspended
+	}
+
+	session, err := service.SessionRepository.FindByID(ctx, service.DB, token.FamilyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return web.TokenResponse{}, exception.ErrInvalidRefreshToken
+		}
+		return web.TokenResponse{}, err
+	}
+
+	now := time.Now()
+	if !session.Active(now) {
+		return web.TokenResponse{}, exception.ErrInvalidRefreshToken
+	}
+
+	session.UserAgent = truncate(client.UserAgent, 255)
+	session.IPAddress = client.IPAddress
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(service.JWTConfig.RefreshExpiry)
+
+	var response web.TokenResponse
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		revoked, err := service.RefreshTokenRepository.Revoke(ctx, tx, token.ID)
+		if err != nil {
+			return err
+		}
+		if !revoked {
+			return exception.ErrRefreshTokenReused
+		}
+
+		err = service.SessionRepository.Update(ctx, tx, &session)
+		if err != nil {
+			return err
+		}
+
+		response, err = service.issue(ctx, tx, user, session.ID)
+		return err
+	})
+	if errors.Is(err, exception.ErrRefreshTokenReused) {
+		return web.TokenResponse{}, service.revokeFamily(ctx, token.FamilyID)
+	}
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	return response, nil
+}
+
+// revokeFamily ends the session a reused refresh token belongs to.
+func (service *TokenServiceImpl) revokeFamily(ctx context.Context, familyID string) error {
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.RefreshTokenRepository.RevokeFamily(ctx, tx, familyID)
+		if err != nil {
+			return err
+		}
+
+		return service.SessionRepository.Revoke(ctx, tx, familyID)
+	})
+	if err != nil {
+		return err
+	}
+	return exception.ErrRefreshTokenReused
+}
+
+func (service *TokenServiceImpl) issue(ctx context.Context, tx *gorm.DB, user entity.User, sessionID string) (web.TokenResponse, error) {
+	accessToken, err := service.signAccessToken(user, sessionID)
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	refreshToken, err := helper.GenerateToken()
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	err = service.RefreshTokenRepository.Save(ctx, tx, &entity.RefreshToken{
+		UserId:       user.ID,
+		FamilyID:     sessionID,
+		TokenHash:    helper.HashToken(refreshToken),
+		TokenVersion: user.TokenVersion,
+		ExpiresAt:    time.Now().Add(service.JWTConfig.RefreshExpiry),
+	})
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	return web.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(service.JWTConfig.Expiry.Seconds()),
+	}, nil
+}
+
+func (service *TokenServiceImpl) signAccessToken(user entity.User, sessionID string) (string, error) {
+	return service.KeySet.Sign(jwt.MapClaims{
+		"jti":           sessionID,
+		"username":      user.Username,
+		"user_id":       user.ID,
+		"token_version": user.TokenVersion,
+		"exp":           time.Now().Add(service.JWTConfig.Expiry).Unix(),
+	})
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context, user entity.User, request web.TwoFactorEnrollReq) (web.TwoFactorEnrollResponse, error)
+	Confirm(ctx context.Context, user entity.User, request web.TwoFactorConfirmReq) (web.RecoveryCodesResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, user entity.User, request web.TwoFactorCodeReq) (web.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, user entity.User, request web.DisableTwoFactorReq) error
+	Login(ctx context.Context, user entity.User, client web.ClientInfo) (web.LoginResponse, error)
+	Verify(ctx context.Context, request web.TwoFactorLoginReq, client web.ClientInfo) (web.TokenResponse, error)
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"meals-app/config"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+	"meals-app/totp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type TwoFactorServiceImpl struct {
+	UserRepository         repository.UserRepository
+	UserTokenRepository    repository.UserTokenRepository
+	RecoveryCodeRepository repository.RecoveryCodeRepository
+	AuditEventRepository   repository.AuditEventRepository
+	DB                     *gorm.DB
+	Validate               *validator.Validate
+	TokenService           TokenService
+	LoginThrottleService   LoginThrottleService
+	TwoFactorConfig        config.TwoFactorConfig
+}
+
+func NewTwoFactorServiceImpl(userRepository repository.UserRepository, userTokenRepository repository.UserTokenRepository, recoveryCodeRepository repository.RecoveryCodeRepository, auditEventRepository repository.AuditEventRepository, DB *gorm.DB, validate *validator.Validate, tokenService TokenService, loginThrottleService LoginThrottleService, twoFactorConfig config.TwoFactorConfig) TwoFactorService {
+	return &TwoFactorServiceImpl{
+		UserRepository:         userRepository,
+		UserTokenRepository:    userTokenRepository,
+		RecoveryCodeRepository: recoveryCodeRepository,
+		AuditEventRepository:   auditEventRepository,
+		DB:                     DB,
+		Validate:               validate,
+		TokenService:           tokenService,
+		LoginThrottleService:   loginThrottleService,
+		TwoFactorConfig:        twoFactorConfig,
+	}
+}
+
+// Enroll stores a new TOTP secret for the user. It takes effect once
+// Confirm proves the authenticator app produces matching codes.
+func (service *TwoFactorServiceImpl) Enroll(ctx context.Context, user entity.User, request web.TwoFactorEnrollReq) (web.TwoFactorEnrollResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.TwoFactorEnrollResponse{}, exception.NewValidationError(err)
+	}
+
+	if user.TOTPEnabled {
+		return web.TwoFactorEnrollResponse{}, exception.ErrTwoFactorEnabled
+	}
+
+	if !helper.VerifyPassword(request.CurrentPassword, user.Password) {
+		return web.TwoFactorEnrollResponse{}, exception.ErrWrongPassword
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return web.TwoFactorEnrollResponse{}, err
+	}
+
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	err = service.UserRepository.Update(ctx, service.DB, &user, "totp_secret", "totp_last_step")
+	if err != nil {
+		return web.TwoFactorEnrollResponse{}, err
+	}
+
+	return web.TwoFactorEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(service.TwoFactorConfig.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (service *TwoFactorServiceImpl) Confirm(ctx context.Context, user entity.User, request web.TwoFactorConfirmReq) (web.RecoveryCodesResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.RecoveryCodesResponse{}, exception.NewValidationError(err)
+	}
+
+	if user.TOTPEnabled {
+		return web.RecoveryCodesResponse{}, exception.ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return web.RecoveryCodesResponse{}, exception.ErrTwoFactorNotEnrolled
+	}
+
+	if !helper.VerifyPassword(request.CurrentPassword, user.Password) {
+		return web.RecoveryCodesResponse{}, exception.ErrWrongPassword
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, request.Code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return web.RecoveryCodesResponse{}, exception.ErrInvalidTwoFactorCode
+	}
+
+	var codes []string
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		user.TOTPEnabled = true
+		user.TOTPLastStep = step
+		err := service.UserRepository.Update(ctx, tx, &user, "totp_enabled", "totp_last_step")
+		if err != nil {
+			return err
+		}
+
+		codes, err = service.replaceRecoveryCodes(ctx, tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return web.RecoveryCodesResponse{}, err
+	}
+
+	return web.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code, used or not, with
+// a new set.
+func (service *TwoFactorServiceImpl) RegenerateRecoveryCodes(ctx context.Context, user entity.User, request web.TwoFactorCodeReq) (web.RecoveryCodesResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.RecoveryCodesResponse{}, exception.NewValidationError(err)
+	}
+
+	if !user.TOTPEnabled {
+		return web.RecoveryCodesResponse{}, exception.ErrTwoFactorNotEnabled
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, request.Code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return web.RecoveryCodesResponse{}, exception.ErrInvalidTwoFactorCode
+	}
+
+	var codes []string
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		user.TOTPLastStep = step
+		err := service.UserRepository.Update(ctx, tx, &user, "totp_last_step")
+		if err != nil {
+			return err
+		}
+
+		codes, err = service.replaceRecoveryCodes(ctx, tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return web.RecoveryCodesResponse{}, err
+	}
+
+	return web.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (service *TwoFactorServiceImpl) Disable(ctx context.Context, user entity.User, request web.DisableTwoFactorReq) error {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return exception.NewValidationError(err)
+	}
+
+	if !user.TOTPEnabled {
+		return exception.ErrTwoFactorNotEnabled
+	}
+
+	if !helper.VerifyPassword(request.Password, user.Password) {
+		return exception.ErrWrongPassword
+	}
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		ok, err := service.checkCode(ctx, tx, &user, request.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return exception.ErrInvalidTwoFactorCode
+		}
+
+		user.TOTPSecret = ""
+		user.TOTPEnabled = false
+		user.TOTPLastStep = 0
+		err = service.UserRepository.Update(ctx, tx, &user, "totp_secret", "totp_enabled", "totp_last_step")
+		if err != nil {
+			return err
+		}
+
+		return service.RecoveryCodeRepository.DeleteByUser(ctx, tx, user.ID)
+	})
+}
+
+// Login finishes a successful first-factor login, whether by password or
+// through a provider. Users without two-factor authentication get their
+// tokens straight away, the others a challenge to answer with Verify.
+func (service *TwoFactorServiceImpl) Login(ctx context.Context, user entity.User, client web.ClientInfo) (web.LoginResponse, error) {
+	if user.Suspended() {
+		return web.LoginResponse{}, loginFailed(ctx, service.DB, service.AuditEventRepository, client, user, user.Email, exception.ErrAccountSuspended)
+	}
+	if user.PasswordResetRequired {
+		return web.LoginResponse{}, loginFailed(ctx, service.DB, service.AuditEventRepository, client, user, user.Email, exception.ErrPasswordResetNeeded)
+	}
+
+	if !user.TOTPEnabled {
+		tokens, err := service.TokenService.Issue(ctx, user, client)
+		if err != nil {
+			return web.LoginResponse{}, err
+		}
+		return web.LoginResponse{TokenResponse: &tokens}, nil
+	}
+
+	var challenge string
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		challenge, err = issueUserToken(ctx, tx, service.UserTokenRepository, user.ID, entity.TokenPurposeTwoFactor, service.TwoFactorConfig.ChallengeExpiry)
+		return err
+	})
+	if err != nil {
+		return web.LoginResponse{}, err
+	}
+
+	return web.LoginResponse{TwoFactorChallengeResponse: &web.TwoFactorChallengeResponse{
+		TwoFactorRequired:  true,
+		ChallengeToken:     challenge,
+		ChallengeExpiresIn: int64(service.TwoFactorConfig.ChallengeExpiry.Seconds()),
+	}}, nil
+}
+
+// Verify exchanges a login challenge and a TOTP or recovery code for
+// tokens. Wrong codes count as failed logins, and the challenge stays
+// usable until it expires so a typo does not mean starting over.
+func (service *TwoFactorServiceImpl) Verify(ctx context.Context, request web.TwoFactorLoginReq, client web.ClientInfo) (web.TokenResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.TokenResponse{}, exception.NewValidationError(err)
+	}
+
+	challenge, err := service.UserTokenRepository.FindByHash(ctx, service.DB, entity.TokenPurposeTwoFactor, helper.HashToken(request.ChallengeToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return web.TokenResponse{}, exception.ErrInvalidChallenge
+		}
+		return web.TokenResponse{}, err
+	}
+
+	user, err := service.UserRepository.FindByID(ctx, service.DB, challenge.UserId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return web.TokenResponse{}, exception.ErrInvalidChallenge
+		}
+		return web.TokenResponse{}, err
+	}
+
+	err = service.LoginThrottleService.Reserve(ctx, user.Email, client.IPAddress)
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := consumeUserToken(ctx, tx, service.UserTokenRepository, entity.TokenPurposeTwoFactor, request.ChallengeToken, exception.ErrInvalidChallenge)
+		if err != nil {
+			return err
+		}
+
+		ok, err := service.checkCode(ctx, tx, &user, request.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return exception.ErrTwoFactorLoginFailed
+		}
+		return nil
+	})
+	if errors.Is(err, exception.ErrTwoFactorLoginFailed) {
+		throttleErr := service.LoginThrottleService.RecordFailure(ctx, user.Email, client.IPAddress)
+		if throttleErr != nil {
+			return web.TokenResponse{}, throttleErr
+		}
+		return web.TokenResponse{}, loginFailed(ctx, service.DB, service.AuditEventRepository, client, user, user.Email, err)
+	}
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	err = service.LoginThrottleService.Reset(ctx, user.Email, client.IPAddress)
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	return service.TokenService.Issue(ctx, user, client)
+}
+
+// checkCode accepts a TOTP code or an unused recovery code, consuming
+// whichever matched.
+func (service *TwoFactorServiceImpl) checkCode(ctx context.Context, tx *gorm.DB, user *entity.User, code string) (bool, error) {
+	step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+	if ok {
+		user.TOTPLastStep = step
+		return true, service.UserRepository.Update(ctx, tx, user, "totp_last_step")
+	}
+
+	return service.RecoveryCodeRepository.Use(ctx, tx, user.ID, helper.HashToken(normalizeRecoveryCode(code)))
+}
+
+func (service *TwoFactorServiceImpl) replaceRecoveryCodes(ctx context.Context, tx *gorm.DB, userID int) ([]string, error) {
+	codes := make([]string, service.TwoFactorConfig.RecoveryCodes)
+	hashes := make([]string, len(codes))
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = helper.HashToken(normalizeRecoveryCode(code))
+	}
+
+	err := service.RecoveryCodeRepository.Replace(ctx, tx, userID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCode returns 50 random bits formatted as xxxxx-xxxxx.
+func generateRecoveryCode() (string, error) {
+	bytes := make([]byte, 7)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(bytes))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
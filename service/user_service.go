@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"io"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+)
+
+type UserService interface {
+	Register(ctx context.Context, request web.UserRegisterReq) (web.UserResponse, error)
+	VerifyEmail(ctx context.Context, request web.VerifyEmailReq) (web.UserResponse, error)
+	ResendVerification(ctx context.Context, user entity.User) error
+	ForgotPassword(ctx context.Context, request web.ForgotPasswordReq) error
+	ResetPassword(ctx context.Context, request web.ResetPasswordReq, client web.ClientInfo) error
+	Login(ctx context.Context, request web.LoginRequest, client web.ClientInfo) (web.LoginResponse, error)
+	Profile(ctx context.Context, user entity.User) web.UserResponse
+	UpdateProfile(ctx context.Context, user entity.User, request web.UserUpdateReq, client web.ClientInfo) (web.UserResponse, error)
+	UpdatePassword(ctx context.Context, user entity.User, request web.ChangePassReq, client web.ClientInfo) (web.UserResponse, error)
+	UpdateImage(ctx context.Context, user entity.User, file io.Reader, filename string) (web.UserResponse, error)
+	FindAllFavorites(ctx context.Context, user entity.User) ([]web.MealResponse, error)
+}
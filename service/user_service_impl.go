@@ -0,0 +1,438 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"meals-app/config"
+	"meals-app/exception"
+	"meals-app/helper"
+	"meals-app/mail"
+	"meals-app/model/entity"
+	"meals-app/model/web"
+	"meals-app/repository"
+	"meals-app/storage"
+	"net/url"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const defaultImageUrl = "https://th.bing.com/th/id/OIP.R9HMSxN_IRyxw9-iE1usugAAAA?rs=1&pid=ImgDetMain"
+
+type UserServiceImpl struct {
+	UserRepository                repository.UserRepository
+	MealRepository                repository.MealRepository
+	UserTokenRepository           repository.UserTokenRepository
+	AuditEventRepository          repository.AuditEventRepository
+	PersonalAccessTokenRepository repository.PersonalAccessTokenRepository
+	DB                            *gorm.DB
+	Validate                      *validator.Validate
+	ImageStore                    storage.ImageStore
+	TwoFactorService              TwoFactorService
+	LoginThrottleService          LoginThrottleService
+	Mailer                        mail.Mailer
+	Config                        *config.Config
+}
+
+func NewUserServiceImpl(userRepository repository.UserRepository, mealRepository repository.MealRepository, userTokenRepository repository.UserTokenRepository, auditEventRepository repository.AuditEventRepository, personalAccessTokenRepository repository.PersonalAccessTokenRepository, DB *gorm.DB, validate *validator.Validate, imageStore storage.ImageStore, twoFactorService TwoFactorService, loginThrottleService LoginThrottleService, mailer mail.Mailer, cfg *config.Config) UserService {
+	return &UserServiceImpl{
+		UserRepository:                userRepository,
+		MealRepository:                mealRepository,
+		UserTokenRepository:           userTokenRepository,
+		AuditEventRepository:          auditEventRepository,
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+		DB:                            DB,
+		Validate:                      validate,
+		ImageStore:                    imageStore,
+		TwoFactorService:              twoFactorService,
+		LoginThrottleService:          loginThrottleService,
+		Mailer:                        mailer,
+		Config:                        cfg,
+	}
+}
+
+func (service *UserServiceImpl) Register(ctx context.Context, request web.UserRegisterReq) (web.UserResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserResponse{}, exception.NewValidationError(err)
+	}
+
+	hashedPassword, err := helper.HashPassword(request.Password)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	user := entity.User{
+		Username: request.Username,
+		Password: hashedPassword,
+		Email:    request.Email,
+		ImageUrl: defaultImageUrl,
+	}
+
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.UserRepository.Save(ctx, tx, &user)
+		if err != nil {
+			return err
+		}
+
+		return service.sendVerification(ctx, tx, user)
+	})
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return web.UserResponse{}, exception.ErrEmailExists
+		}
+		return web.UserResponse{}, err
+	}
+
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) VerifyEmail(ctx context.Context, request web.VerifyEmailReq) (web.UserResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserResponse{}, exception.NewValidationError(err)
+	}
+
+	var user entity.User
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeUserToken(ctx, tx, service.UserTokenRepository, entity.TokenPurposeEmailVerification, request.Token, exception.ErrInvalidVerification)
+		if err != nil {
+			return err
+		}
+
+		user, err = service.UserRepository.FindByID(ctx, tx, token.UserId)
+		if err != nil {
+			return err
+		}
+
+		user.IsVerified = true
+		return service.UserRepository.Update(ctx, tx, &user, "is_verified")
+	})
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) ResendVerification(ctx context.Context, user entity.User) error {
+	if user.IsVerified {
+		return exception.ErrAlreadyVerified
+	}
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		return service.sendVerification(ctx, tx, user)
+	})
+}
+
+// ForgotPassword mails a reset token when the email belongs to an account.
+// Unknown emails succeed silently so the endpoint cannot be used to find
+// out who is registered.
+func (service *UserServiceImpl) ForgotPassword(ctx context.Context, request web.ForgotPasswordReq) error {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return exception.NewValidationError(err)
+	}
+
+	user, err := service.UserRepository.FindByEmail(ctx, service.DB, request.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		return sendPasswordReset(ctx, tx, service.UserTokenRepository, service.Mailer, service.Config.Account, user,
+			"If you did not ask for a password reset, you can ignore this email.")
+	})
+}
+
+// sendPasswordReset mails user a password reset token, or a link to
+// PasswordResetURL when one is configured, ending with note.
+func sendPasswordReset(ctx context.Context, tx *gorm.DB, userTokenRepository repository.UserTokenRepository, mailer mail.Mailer, account config.AccountConfig, user entity.User, note string) error {
+	secret, err := issueUserToken(ctx, tx, userTokenRepository, user.ID, entity.TokenPurposePasswordReset, account.PasswordResetExpiry)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nUse the token below to choose a new password:\n\n%s\n", user.Username, secret)
+	if account.PasswordResetURL != "" {
+		link := account.PasswordResetURL + "?token=" + url.QueryEscape(secret)
+		body = fmt.Sprintf("Hi %s,\n\nChoose a new password by opening the link below:\n\n%s\n", user.Username, link)
+	}
+
+	return mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    body + "\n" + note + "\n",
+	})
+}
+
+// ResetPassword sets a new password using a mailed reset token, bumps the
+// token version so every existing session is logged out and revokes the
+// personal access tokens. The token proves the email address, so the
+// account counts as verified afterwards. A password rejected by the policy
+// leaves the token unused.
+func (service *UserServiceImpl) ResetPassword(ctx context.Context, request web.ResetPasswordReq, client web.ClientInfo) error {
+	err := service.Validate.StructPartial(request, "Token")
+	if err != nil {
+		return exception.NewValidationError(err)
+	}
+
+	return service.DB.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeUserToken(ctx, tx, service.UserTokenRepository, entity.TokenPurposePasswordReset, request.Token, exception.ErrInvalidPasswordReset)
+		if err != nil {
+			return err
+		}
+
+		user, err := service.UserRepository.FindByID(ctx, tx, token.UserId)
+		if err != nil {
+			return err
+		}
+
+		request.Username = user.Username
+		request.Email = user.Email
+		err = service.Validate.Struct(request)
+		if err != nil {
+			return exception.NewValidationError(err)
+		}
+
+		err = service.UserTokenRepository.InvalidateByUser(ctx, tx, user.ID, entity.TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		hash, err := helper.HashPassword(request.Password)
+		if err != nil {
+			return err
+		}
+
+		user.Password = hash
+		user.PasswordResetRequired = false
+		user.IsVerified = true
+		err = service.UserRepository.Update(ctx, tx, &user, "password", "password_reset_required", "is_verified")
+		if err != nil {
+			return err
+		}
+
+		err = service.UserRepository.IncrementTokenVersion(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = service.PersonalAccessTokenRepository.RevokeByUser(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, tx, service.AuditEventRepository, client, userEvent(entity.AuditPasswordReset, user.ID, user.ID))
+	})
+}
+
+// Login answers an unknown email exactly like a wrong password, including
+// the time spent hashing, and counts both as failed attempts.
+func (service *UserServiceImpl) Login(ctx context.Context, request web.LoginRequest, client web.ClientInfo) (web.LoginResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.LoginResponse{}, exception.NewValidationError(err)
+	}
+
+	err = service.LoginThrottleService.Reserve(ctx, request.Email, client.IPAddress)
+	if err != nil {
+		return web.LoginResponse{}, err
+	}
+
+	user, err := service.UserRepository.FindByEmail(ctx, service.DB, request.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.LoginResponse{}, err
+	}
+
+	hash := user.Password
+	if hash == "" {
+		hash = dummyPasswordHash()
+	}
+
+	valid := helper.VerifyPassword(request.Password, hash) && user.ID != 0
+	if !valid {
+		err = service.LoginThrottleService.RecordFailure(ctx, request.Email, client.IPAddress)
+		if err != nil {
+			return web.LoginResponse{}, err
+		}
+		return web.LoginResponse{}, loginFailed(ctx, service.DB, service.AuditEventRepository, client, user, request.Email, exception.ErrInvalidCredentials)
+	}
+
+	err = service.LoginThrottleService.Reset(ctx, request.Email, client.IPAddress)
+	if err != nil {
+		return web.LoginResponse{}, err
+	}
+
+	return service.TwoFactorService.Login(ctx, user, client)
+}
+
+func (service *UserServiceImpl) Profile(ctx context.Context, user entity.User) web.UserResponse {
+	return helper.ToUserResponse(user)
+}
+
+func (service *UserServiceImpl) UpdateProfile(ctx context.Context, user entity.User, request web.UserUpdateReq, client web.ClientInfo) (web.UserResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserResponse{}, exception.NewValidationError(err)
+	}
+
+	oldEmail := user.Email
+	emailChanged := request.Email != "" && request.Email != user.Email
+	columns := []string{"username"}
+	if emailChanged {
+		user.Email = request.Email
+		user.IsVerified = false
+		columns = append(columns, "email", "is_verified")
+	}
+
+	if request.Username != "" {
+		user.Username = request.Username
+	}
+
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.UserRepository.Update(ctx, tx, &user, columns...)
+		if err != nil || !emailChanged {
+			return err
+		}
+
+		err = service.UserRepository.IncrementTokenVersion(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		// Tokens mailed to the old address must not prove the new one.
+		for _, purpose := range []string{entity.TokenPurposePasswordReset, entity.TokenPurposeAccountDeletion} {
+			err = service.UserTokenRepository.InvalidateByUser(ctx, tx, user.ID, purpose)
+			if err != nil {
+				return err
+			}
+		}
+
+		event := userEvent(entity.AuditEmailChange, user.ID, user.ID)
+		event.Detail = oldEmail + " to " + user.Email
+		err = recordAuditEvent(ctx, tx, service.AuditEventRepository, client, event)
+		if err != nil {
+			return err
+		}
+
+		return service.sendVerification(ctx, tx, user)
+	})
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return web.UserResponse{}, exception.ErrEmailExists
+		}
+		return web.UserResponse{}, err
+	}
+
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) UpdatePassword(ctx context.Context, user entity.User, request web.ChangePassReq, client web.ClientInfo) (web.UserResponse, error) {
+	request.Username = user.Username
+	request.Email = user.Email
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserResponse{}, exception.NewValidationError(err)
+	}
+
+	if !helper.VerifyPassword(request.CurrentPassword, user.Password) {
+		event := userEvent(entity.AuditPasswordChange, user.ID, user.ID)
+		event.Outcome = entity.AuditFailure
+		event.Detail = exception.ErrWrongPassword.Error()
+		err = recordAuditEvent(ctx, service.DB, service.AuditEventRepository, client, event)
+		if err != nil {
+			return web.UserResponse{}, err
+		}
+		return web.UserResponse{}, exception.ErrWrongPassword
+	}
+
+	hash, err := helper.HashPassword(request.Password)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	user.Password = hash
+
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.UserRepository.Update(ctx, tx, &user, "password")
+		if err != nil {
+			return err
+		}
+
+		err = service.UserRepository.IncrementTokenVersion(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = service.PersonalAccessTokenRepository.RevokeByUser(ctx, tx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, tx, service.AuditEventRepository, client, userEvent(entity.AuditPasswordChange, user.ID, user.ID))
+	})
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) UpdateImage(ctx context.Context, user entity.User, file io.Reader, filename string) (web.UserResponse, error) {
+	imageUrl, err := service.ImageStore.Upload(ctx, file, filename)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	user.ImageUrl = imageUrl
+
+	err = service.UserRepository.Update(ctx, service.DB, &user, "image_url")
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) FindAllFavorites(ctx context.Context, user entity.User) ([]web.MealResponse, error) {
+	meals, err := service.MealRepository.FindFavoritesByUser(ctx, service.DB, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.ToMealResponses(meals), nil
+}
+
+// sendVerification mails a new verification link. It runs inside tx so a
+// failed delivery rolls back the change that triggered it.
+func (service *UserServiceImpl) sendVerification(ctx context.Context, tx *gorm.DB, user entity.User) error {
+	secret, err := issueUserToken(ctx, tx, service.UserTokenRepository, user.ID, entity.TokenPurposeEmailVerification, service.Config.Account.VerificationExpiry)
+	if err != nil {
+		return err
+	}
+
+	link := service.Config.App.BaseURL + "/api/verify-email?token=" + url.QueryEscape(secret)
+	return service.Mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body:    fmt.Sprintf("Hi %s,\n\nConfirm your email address by opening the link below:\n\n%s\n", user.Username, link),
+	})
+}
+
+// dummyPasswordHash is compared against when the email is unknown so the
+// response takes as long as for a wrong password.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := helper.HashPassword("not a real password")
+	return hash
+})
+
+func isDuplicateEntry(err error) bool {
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"meals-app/helper"
+	"meals-app/model/entity"
+	"meals-app/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// issueUserToken replaces any outstanding token of the given purpose with a
+// new one and returns its secret.
+func issueUserToken(ctx context.Context, tx *gorm.DB, userTokenRepository repository.UserTokenRepository, userID int, purpose string, expiry time.Duration) (string, error) {
+	err := userTokenRepository.InvalidateByUser(ctx, tx, userID, purpose)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := helper.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = userTokenRepository.Save(ctx, tx, &entity.UserToken{
+		UserId:    userID,
+		Purpose:   purpose,
+		TokenHash: helper.HashToken(secret),
+		ExpiresAt: time.Now().Add(expiry),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// consumeUserToken marks the token with the given secret as used, failing
+// with invalid when it is unknown, expired or already used.
+func consumeUserToken(ctx context.Context, tx *gorm.DB, userTokenRepository repository.UserTokenRepository, purpose string, secret string, invalid error) (entity.UserToken, error) {
+	token, err := userTokenRepository.FindByHash(ctx, tx, purpose, helper.HashToken(secret))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.UserToken{}, invalid
+		}
+		return entity.UserToken{}, err
+	}
+
+	if token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return entity.UserToken{}, invalid
+	}
+
+	used, err := userTokenRepository.MarkUsed(ctx, tx, token.ID)
+	if err != nil {
+		return entity.UserToken{}, err
+	}
+	if !used {
+		return entity.UserToken{}, invalid
+	}
+
+	return token, nil
+}
@@ -0,0 +1,90 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is one JWT key. PrivateKey is nil for keys that are only kept to
+// verify tokens issued before a rotation. For HS256 both fields hold the
+// shared secret as []byte.
+type Key struct {
+	ID         string
+	PrivateKey any
+	PublicKey  any
+}
+
+// KeySet signs tokens with its current key and verifies them with any of
+// its keys, selected by the kid header.
+type KeySet struct {
+	Method  jwt.SigningMethod
+	Current Key
+	Keys    []Key
+}
+
+// NewKeySet checks that every key fits the algorithm and picks the signing
+// key: currentID when set, otherwise the first key with a private part.
+func NewKeySet(algorithm string, currentID string, keys []Key) (*KeySet, error) {
+	method := jwt.GetSigningMethod(algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+	}
+
+	set := &KeySet{Method: method}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("duplicate JWT key id %q", key.ID)
+		}
+		seen[key.ID] = true
+
+		err := checkKeyType(method, key)
+		if err != nil {
+			return nil, fmt.Errorf("JWT key %q: %w", key.ID, err)
+		}
+
+		if set.Current.PrivateKey == nil && key.PrivateKey != nil && (currentID == "" || currentID == key.ID) {
+			set.Current = key
+		}
+		set.Keys = append(set.Keys, key)
+	}
+
+	if set.Current.PrivateKey == nil {
+		return nil, errors.New("no JWT signing key with a private key")
+	}
+
+	return set, nil
+}
+
+func (set *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(set.Method, claims)
+	token.Header["kid"] = set.Current.ID
+	return token.SignedString(set.Current.PrivateKey)
+}
+
+// Symmetric reports whether the keys are shared secrets, which must never
+// be published.
+func (set *KeySet) Symmetric() bool {
+	_, ok := set.Method.(*jwt.SigningMethodHMAC)
+	return ok
+}
+
+func checkKeyType(method jwt.SigningMethod, key Key) error {
+	var ok bool
+	switch method.(type) {
+	case *jwt.SigningMethodHMAC:
+		_, ok = key.PublicKey.([]byte)
+	case *jwt.SigningMethodRSA:
+		_, ok = key.PublicKey.(*rsa.PublicKey)
+	case *jwt.SigningMethodEd25519:
+		_, ok = key.PublicKey.(ed25519.PublicKey)
+	}
+	if !ok {
+		return fmt.Errorf("key does not match algorithm %s", method.Alg())
+	}
+	return nil
+}
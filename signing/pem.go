@@ -0,0 +1,51 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ParsePrivateKey reads a PEM encoded private key for the algorithm and
+// returns a Key holding both halves.
+func ParsePrivateKey(id string, algorithm string, content []byte) (Key, error) {
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(content)
+		if err != nil {
+			return Key{}, err
+		}
+		return Key{ID: id, PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}, nil
+	case jwt.SigningMethodEdDSA.Alg():
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(content)
+		if err != nil {
+			return Key{}, err
+		}
+		edKey := privateKey.(ed25519.PrivateKey)
+		return Key{ID: id, PrivateKey: edKey, PublicKey: edKey.Public()}, nil
+	}
+	return Key{}, fmt.Errorf("unsupported JWT algorithm %q for key files", algorithm)
+}
+
+// ParsePublicKey reads a PEM encoded public key for a key that can only
+// verify tokens.
+func ParsePublicKey(id string, algorithm string, content []byte) (Key, error) {
+	var publicKey any
+	var err error
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		var rsaKey *rsa.PublicKey
+		rsaKey, err = jwt.ParseRSAPublicKeyFromPEM(content)
+		publicKey = rsaKey
+	case jwt.SigningMethodEdDSA.Alg():
+		publicKey, err = jwt.ParseEdPublicKeyFromPEM(content)
+	default:
+		err = fmt.Errorf("unsupported JWT algorithm %q for key files", algorithm)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, PublicKey: publicKey}, nil
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"io"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/google/uuid"
+)
+
+type CloudinaryImageStore struct {
+	Cld    *cloudinary.Cloudinary
+	Folder string
+}
+
+func NewCloudinaryImageStore(cld *cloudinary.Cloudinary, folder string) ImageStore {
+	return &CloudinaryImageStore{
+		Cld:    cld,
+		Folder: folder,
+	}
+}
+
+// Upload stores the image under a generated public ID, as the client's file
+// name is neither unique nor trusted. Cloudinary checks the format itself.
+func (store *CloudinaryImageStore) Upload(ctx context.Context, file io.Reader, filename string) (string, error) {
+	param := uploader.UploadParams{
+		PublicID:       uuid.NewString(),
+		Folder:         store.Folder,
+		AllowedFormats: allowedImageFormats,
+	}
+
+	uploadResult, err := store.Cld.Upload.Upload(ctx, file, param)
+	if err != nil {
+		return "", err
+	}
+
+	return uploadResult.SecureURL, nil
+}
+
+func (store *CloudinaryImageStore) Ping(ctx context.Context) error {
+	_, err := store.Cld.Admin.Ping(ctx)
+	return err
+}
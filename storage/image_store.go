@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+var allowedImageFormats = []string{"jpg", "png", "jpeg"}
+
+type ImageStore interface {
+	Upload(ctx context.Context, file io.Reader, filename string) (string, error)
+	Ping(ctx context.Context) error
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"meals-app/exception"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// LocalImageStore writes uploads to Dir and builds URLs that are served
+// back by a static route mounted at Route.
+type LocalImageStore struct {
+	Dir     string
+	Route   string
+	BaseURL string
+}
+
+func NewLocalImageStore(dir string, route string, baseURL string) ImageStore {
+	return &LocalImageStore{
+		Dir:     dir,
+		Route:   route,
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Upload only takes the extension from filename. Files are stored under a
+// generated name so that uploads never replace each other.
+func (store *LocalImageStore) Upload(ctx context.Context, file io.Reader, filename string) (string, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	if !slices.Contains(allowedImageFormats, ext) {
+		return "", exception.ErrInvalidImageFormat
+	}
+	name := uuid.NewString() + "." + ext
+
+	err := os.MkdirAll(store.Dir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	dst, err := os.OpenFile(filepath.Join(store.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, file)
+	if err != nil {
+		return "", err
+	}
+
+	return store.BaseURL + store.Route + "/" + name, nil
+}
+
+func (store *LocalImageStore) Ping(ctx context.Context) error {
+	err := os.MkdirAll(store.Dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(store.Dir, ".ping-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+
+	return os.Remove(file.Name())
+}
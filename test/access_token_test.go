@@ -0,0 +1,171 @@
+package test
+
+import (
+	"fmt"
+	"meals-app/model/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accessTokenResult struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Token      string     `json:"token"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+func (h *harness) createAccessToken(token string, scopes ...string) accessTokenResult {
+	h.t.Helper()
+
+	code, resp := h.request("POST", "/api/users/tokens", token, fiber.Map{
+		"name":            "ci",
+		"scopes":          scopes,
+		"expires_in_days": 30,
+	})
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var result accessTokenResult
+	resp.decode(h.t, &result)
+	return result
+}
+
+func (h *harness) accessTokens(token string) []accessTokenResult {
+	h.t.Helper()
+
+	code, resp := h.request("GET", "/api/users/tokens", token, nil)
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var result []accessTokenResult
+	resp.decode(h.t, &result)
+	return result
+}
+
+func TestAccessTokenScopes(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	created := h.createAccessToken(token, entity.ScopeMealsRead, entity.ScopeMealsWrite)
+	assert.True(t, strings.HasPrefix(created.Token, entity.AccessTokenPrefix))
+	assert.Equal(t, []string{entity.ScopeMealsRead, entity.ScopeMealsWrite}, created.Scopes)
+
+	meal := h.createMeal(created.Token, "fried rice")
+	code, _ := h.request("GET", mealPath(meal.ID, ""), created.Token, nil)
+	assert.Equal(t, 200, code)
+
+	code, resp := h.request("POST", mealPath(meal.ID, "/favorites"), created.Token, nil)
+	assert.Equal(t, 403, code)
+	assert.Equal(t, "access token is missing the favorites:write scope", resp.errorMessage(t))
+
+	// Account settings need a real login.
+	for _, path := range []string{"/api/users/sessions", "/api/users/tokens"} {
+		code, resp = h.request("GET", path, created.Token, nil)
+		assert.Equal(t, 403, code, path)
+		assert.Equal(t, "personal access tokens cannot be used here, login instead", resp.errorMessage(t))
+	}
+
+	tokens := h.accessTokens(token)
+	require.Len(t, tokens, 1)
+	assert.Empty(t, tokens[0].Token)
+	assert.NotNil(t, tokens[0].LastUsedAt)
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	_, otherToken := h.newUser("sari")
+	created := h.createAccessToken(token, entity.ScopeProfileRead)
+
+	code, _ := h.request("GET", "/api/users/", created.Token, nil)
+	require.Equal(t, 200, code)
+
+	path := fmt.Sprintf("/api/users/tokens/%d", created.ID)
+	code, _ = h.request("DELETE", path, otherToken, nil)
+	assert.Equal(t, 404, code)
+
+	code, _ = h.request("DELETE", path, token, nil)
+	require.Equal(t, 200, code)
+
+	code, resp := h.request("GET", "/api/users/", created.Token, nil)
+	assert.Equal(t, 401, code)
+	assert.Equal(t, "invalid or expired access token", resp.errorMessage(t))
+	assert.Empty(t, h.accessTokens(token))
+}
+
+func TestExpiredAccessToken(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	created := h.createAccessToken(token, entity.ScopeProfileRead)
+
+	require.NoError(t, h.db.Model(&entity.PersonalAccessToken{}).Where("id = ?", created.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+	code, _ := h.request("GET", "/api/users/", created.Token, nil)
+	assert.Equal(t, 401, code)
+}
+
+func TestCreateAccessTokenValidation(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, resp := h.request("POST", "/api/users/tokens", token, fiber.Map{
+		"name":            "ci",
+		"scopes":          []string{"admin"},
+		"expires_in_days": 400,
+	})
+	assert.Equal(t, 422, code, string(resp.Data))
+}
+
+func TestPasswordChangesRevokeAccessTokens(t *testing.T) {
+	h := newHarness(t)
+	userID, token := h.newUser("alfan")
+	adminToken := h.newAdmin()
+
+	assertRevoked := func(accessToken string) {
+		t.Helper()
+		code, resp := h.request("GET", "/api/users/", accessToken, nil)
+		assert.Equal(t, 401, code)
+		assert.Equal(t, "invalid or expired access token", resp.errorMessage(t))
+	}
+
+	created := h.createAccessToken(token, entity.ScopeProfileRead)
+	code, resp := h.request("PUT", "/api/users/change-password", token, fiber.Map{
+		"current_password": "Secret123!",
+		"password":         "Changed123!",
+	})
+	require.Equal(t, 200, code, string(resp.Data))
+	assertRevoked(created.Token)
+
+	token = h.login("alfan@example.com", "Changed123!")
+	created = h.createAccessToken(token, entity.ScopeProfileRead)
+	h.forgotPassword("alfan@example.com")
+	code, resp = h.request("POST", "/api/password/reset", "", fiber.Map{
+		"token":    h.mailToken("alfan@example.com"),
+		"password": "ResetAgain123!",
+	})
+	require.Equal(t, 200, code, string(resp.Data))
+	assertRevoked(created.Token)
+
+	token = h.login("alfan@example.com", "ResetAgain123!")
+	created = h.createAccessToken(token, entity.ScopeProfileRead)
+	code, resp = h.request("POST", adminUserPath(userID, "/password-reset"), adminToken, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+	assertRevoked(created.Token)
+}
+
+func TestAccessTokenRefusedWhilePasswordResetRequired(t *testing.T) {
+	h := newHarness(t)
+	userID, token := h.newUser("alfan")
+	created := h.createAccessToken(token, entity.ScopeProfileRead)
+
+	require.NoError(t, h.db.Model(&entity.User{}).Where("id = ?", userID).Update("password_reset_required", true).Error)
+
+	code, resp := h.request("GET", "/api/users/", created.Token, nil)
+	assert.Equal(t, 403, code)
+	assert.Equal(t, "a password reset is required, check your email", resp.errorMessage(t))
+}
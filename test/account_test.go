@@ -0,0 +1,235 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"meals-app/config"
+	"meals-app/model/entity"
+	"meals-app/repository"
+	"meals-app/service"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportResult struct {
+	Profile struct {
+		Username string `json:"username"`
+	} `json:"profile"`
+	Recipes   []mealResult `json:"recipes"`
+	Favorites []mealResult `json:"favorites"`
+}
+
+func (h *harness) download(path string, token string) (int, string, []byte) {
+	h.t.Helper()
+
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	resp, err := h.app.Test(req, -1)
+	require.NoError(h.t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(h.t, err)
+	return resp.StatusCode, resp.Header.Get(fiber.HeaderContentDisposition), body
+}
+
+// purgeAccounts runs the purge-accounts command after moving every
+// scheduled deletion into the past.
+func (h *harness) purgeAccounts() int {
+	h.t.Helper()
+
+	require.NoError(h.t, h.db.Model(&entity.User{}).Where("deletion_scheduled_at IS NOT NULL").
+		Update("deletion_scheduled_at", time.Now().Add(-time.Minute)).Error)
+
+	validate, _ := config.NewValidator(h.cfg.Password)
+	accountService := service.NewAccountServiceImpl(repository.NewUserRepositoryImpl(), repository.NewUserTokenRepositoryImpl(), repository.NewMealRepositoryImpl(), repository.NewLoginThrottleRepositoryImpl(), h.db, validate, config.NewMailer(h.cfg.Mail), h.cfg.Account)
+
+	deleted, err := accountService.PurgeDeleted(context.Background())
+	require.NoError(h.t, err)
+	return deleted
+}
+
+func TestExportAccountData(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	_, otherToken := h.newUser("sari")
+	h.createMeal(token, "fried rice")
+	favorite := h.createMeal(otherToken, "soto")
+	code, _ := h.request("POST", mealPath(favorite.ID, "/favorites"), token, nil)
+	require.Equal(t, 200, code)
+
+	code, disposition, body := h.download("/api/users/export?format=json", token)
+	require.Equal(t, 200, code, string(body))
+	assert.Contains(t, disposition, "meals-app-export.json")
+
+	var export exportResult
+	require.NoError(t, json.Unmarshal(body, &export))
+	assert.Equal(t, "alfan", export.Profile.Username)
+	require.Len(t, export.Recipes, 1)
+	assert.Equal(t, "fried rice", export.Recipes[0].Name)
+	require.Len(t, export.Favorites, 1)
+	assert.Equal(t, "soto", export.Favorites[0].Name)
+
+	code, disposition, body = h.download("/api/users/export", token)
+	require.Equal(t, 200, code, string(body))
+	assert.Contains(t, disposition, "meals-app-export.zip")
+
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"profile.json", "recipes.json", "favorites.json"}, names)
+
+	code, _ = h.request("GET", "/api/users/export?format=xml", token, nil)
+	assert.Equal(t, 400, code)
+}
+
+func TestScheduleAndCancelAccountDeletion(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, resp := h.request("POST", "/api/users/deletion", token, fiber.Map{"password": "Wrong123!"})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "current password is incorrect", resp.errorMessage(t))
+
+	code, resp = h.request("POST", "/api/users/deletion", token, fiber.Map{"password": "Secret123!"})
+	require.Equal(t, 200, code, string(resp.Data))
+	var scheduled struct {
+		DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+	}
+	resp.decode(t, &scheduled)
+	assert.WithinDuration(t, time.Now().Add(h.cfg.Account.DeletionGracePeriod), scheduled.DeletionScheduledAt, time.Minute)
+	assert.Contains(t, h.mails("alfan@example.com")[1], "Your account will be deleted")
+
+	// The account keeps working during the grace period.
+	code, resp = h.request("GET", "/api/users/", token, nil)
+	require.Equal(t, 200, code)
+	assert.NotContains(t, string(resp.Data), `"deletion_scheduled_at":null`)
+
+	code, _ = h.request("DELETE", "/api/users/deletion", token, nil)
+	require.Equal(t, 200, code)
+	code, _ = h.request("DELETE", "/api/users/deletion", token, nil)
+	assert.Equal(t, 400, code)
+
+	assert.Zero(t, h.purgeAccounts())
+	h.login("alfan@example.com", "Secret123!")
+}
+
+var deletionTokenPattern = regexp.MustCompile(`delete your account:\r\n\r\n(\S+)`)
+
+// deletionToken returns the token of the last account deletion mail to to.
+func (h *harness) deletionToken(to string) string {
+	h.t.Helper()
+
+	messages := h.mails(to)
+	require.NotEmpty(h.t, messages, "no mail sent to %s", to)
+
+	match := deletionTokenPattern.FindStringSubmatch(messages[len(messages)-1])
+	require.NotNil(h.t, match)
+	return match[1]
+}
+
+func TestDeleteProviderAccountWithMailedToken(t *testing.T) {
+	h, issuer := newOIDCHarness(t)
+	code, resp := h.oidcLogin(issuer, jwt.MapClaims{"sub": "user-1", "email": "dewi@example.com", "email_verified": true, "name": "dewi"})
+	require.Equal(t, 200, code, string(resp.Data))
+	var tokens tokenResult
+	resp.decode(t, &tokens)
+	_, otherToken := h.newUser("sari")
+
+	code, resp = h.request("POST", "/api/users/deletion", tokens.AccessToken, fiber.Map{})
+	assert.Equal(t, 422, code, string(resp.Data))
+
+	code, resp = h.request("POST", "/api/users/deletion/confirmation", otherToken, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+	otherSecret := h.deletionToken("sari@example.com")
+
+	code, resp = h.request("POST", "/api/users/deletion/confirmation", tokens.AccessToken, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+	secret := h.deletionToken("dewi@example.com")
+
+	// Another user's token does not delete this account.
+	code, resp = h.request("POST", "/api/users/deletion", tokens.AccessToken, fiber.Map{"token": otherSecret})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "invalid or expired account deletion token", resp.errorMessage(t))
+
+	code, resp = h.request("POST", "/api/users/deletion", tokens.AccessToken, fiber.Map{"token": secret})
+	require.Equal(t, 200, code, string(resp.Data))
+	assert.Equal(t, 1, h.purgeAccounts())
+
+	code, _ = h.request("POST", "/api/users/deletion", otherToken, fiber.Map{"token": otherSecret})
+	assert.Equal(t, 200, code)
+}
+
+func TestPurgeAnonymizesRecipes(t *testing.T) {
+	h := newHarness(t)
+	userID, token := h.newUser("alfan")
+	_, otherToken := h.newUser("sari")
+	meal := h.createMeal(token, "fried rice")
+	code, _ := h.request("POST", mealPath(meal.ID, "/favorites"), otherToken, nil)
+	require.Equal(t, 200, code)
+	h.createAccessToken(token, entity.ScopeMealsRead)
+
+	code, resp := h.request("POST", "/api/users/deletion", token, fiber.Map{"password": "Secret123!"})
+	require.Equal(t, 200, code, string(resp.Data))
+	assert.Equal(t, 1, h.purgeAccounts())
+
+	var count int64
+	require.NoError(t, h.db.Model(&entity.User{}).Where("id = ?", userID).Count(&count).Error)
+	assert.Zero(t, count)
+
+	// Everything else the account owned goes with it.
+	for _, table := range []string{"sessions", "refresh_tokens", "personal_access_tokens", "user_tokens"} {
+		require.NoError(t, h.db.Table(table).Where("user_id = ?", userID).Count(&count).Error)
+		assert.Zero(t, count, table)
+	}
+
+	code, resp = h.request("GET", mealPath(meal.ID, ""), otherToken, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+	var anonymized mealResult
+	resp.decode(t, &anonymized)
+	assert.NotEqual(t, userID, anonymized.UserId)
+	assert.Equal(t, []string{"cook rice", "fry egg", "mix"}, anonymized.Steps)
+
+	code, _ = h.request("POST", "/api/login", "", fiber.Map{"email": "alfan@example.com", "password": "Secret123!"})
+	assert.Equal(t, 401, code)
+
+	// The email address can be used for a new account.
+	h.newUser("alfan")
+}
+
+func TestPurgeDeletesRecipes(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Account.DeletedRecipes = "delete"
+	})
+	_, token := h.newUser("alfan")
+	_, otherToken := h.newUser("sari")
+	meal := h.createMeal(token, "fried rice")
+	code, _ := h.request("POST", mealPath(meal.ID, "/favorites"), otherToken, nil)
+	require.Equal(t, 200, code)
+
+	code, resp := h.request("POST", "/api/users/deletion", token, fiber.Map{"password": "Secret123!"})
+	require.Equal(t, 200, code, string(resp.Data))
+	assert.Equal(t, 1, h.purgeAccounts())
+
+	code, _ = h.request("GET", mealPath(meal.ID, ""), otherToken, nil)
+	assert.Equal(t, 404, code)
+
+	var count int64
+	require.NoError(t, h.db.Model(&entity.MealRecipeStep{}).Count(&count).Error)
+	assert.Zero(t, count)
+	require.NoError(t, h.db.Model(&entity.User{}).Count(&count).Error)
+	assert.EqualValues(t, 1, count)
+}
@@ -0,0 +1,193 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"meals-app/model/entity"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type adminUserResult struct {
+	ID                    int        `json:"id"`
+	Username              string     `json:"username"`
+	Role                  string     `json:"role"`
+	SuspendedAt           *time.Time `json:"suspended_at"`
+	SuspensionReason      string     `json:"suspension_reason"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+}
+
+type pageMetaResult struct {
+	Page    int   `json:"page"`
+	PerPage int   `json:"per_page"`
+	Total   int64 `json:"total"`
+}
+
+func (h *harness) newAdmin() string {
+	h.t.Helper()
+
+	adminID, _ := h.newUser("admin")
+	h.setRole(adminID, entity.RoleAdmin)
+	return h.login("admin@example.com", "Secret123!")
+}
+
+func adminUserPath(userID int, suffix string) string {
+	return fmt.Sprintf("/api/admin/users/%d%s", userID, suffix)
+}
+
+func TestAdminSearchUsers(t *testing.T) {
+	h := newHarness(t)
+	adminToken := h.newAdmin()
+	for _, name := range []string{"alfan", "sari", "salma"} {
+		h.newUser(name)
+	}
+
+	code, resp := h.request("GET", "/api/admin/users?q=SA&per_page=1&page=2", adminToken, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+
+	var users []adminUserResult
+	resp.decode(t, &users)
+	require.Len(t, users, 1)
+	assert.Equal(t, "salma", users[0].Username)
+
+	var meta pageMetaResult
+	require.NoError(t, json.Unmarshal(resp.Meta, &meta))
+	assert.Equal(t, pageMetaResult{Page: 2, PerPage: 1, Total: 2}, meta)
+
+	code, resp = h.request("GET", "/api/admin/users?role=admin", adminToken, nil)
+	require.Equal(t, 200, code)
+	resp.decode(t, &users)
+	require.Len(t, users, 1)
+	assert.Equal(t, "admin", users[0].Username)
+
+	code, _ = h.request("GET", "/api/admin/users?per_page=500", adminToken, nil)
+	assert.Equal(t, 422, code)
+}
+
+func TestAdminUpdateRole(t *testing.T) {
+	h := newHarness(t)
+	adminToken := h.newAdmin()
+	userID, userToken := h.newUser("alfan")
+
+	code, _ := h.request("GET", "/api/admin/users", userToken, nil)
+	assert.Equal(t, 403, code)
+
+	code, resp := h.request("PUT", adminUserPath(userID, "/role"), adminToken, fiber.Map{"role": "owner"})
+	assert.Equal(t, 422, code, string(resp.Data))
+
+	code, resp = h.request("PUT", adminUserPath(userID, "/role"), adminToken, fiber.Map{"role": entity.RoleModerator})
+	require.Equal(t, 200, code, string(resp.Data))
+	var user adminUserResult
+	resp.decode(t, &user)
+	assert.Equal(t, entity.RoleModerator, user.Role)
+
+	// The new role applies to tokens issued before the change.
+	h.createMeal(adminToken, "fried rice")
+	code, _ = h.request("POST", mealPath(1, "/hide"), userToken, nil)
+	assert.Equal(t, 200, code)
+
+	code, resp = h.request("PUT", adminUserPath(1, "/role"), adminToken, fiber.Map{"role": entity.RoleUser})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "you cannot change the role of or suspend your own account", resp.errorMessage(t))
+}
+
+func TestAdminSuspendUser(t *testing.T) {
+	h := newHarness(t)
+	adminToken := h.newAdmin()
+	userID, userToken := h.newUser("alfan")
+	tokens := h.loginTokens("alfan@example.com", "Secret123!")
+	accessToken := h.createAccessToken(userToken, entity.ScopeProfileRead)
+
+	code, resp := h.request("POST", adminUserPath(userID, "/suspend"), adminToken, fiber.Map{"reason": "spam"})
+	require.Equal(t, 200, code, string(resp.Data))
+	var user adminUserResult
+	resp.decode(t, &user)
+	assert.NotNil(t, user.SuspendedAt)
+	assert.Equal(t, "spam", user.SuspensionReason)
+
+	code, _ = h.request("POST", adminUserPath(userID, "/suspend"), adminToken, nil)
+	assert.Equal(t, 409, code)
+
+	code, _ = h.request("GET", "/api/users/", userToken, nil)
+	assert.Equal(t, 401, code)
+	code, resp = h.request("GET", "/api/users/", accessToken.Token, nil)
+	assert.Equal(t, 403, code)
+	assert.Equal(t, "account suspended", resp.errorMessage(t))
+	code, _ = h.request("POST", "/api/token/refresh", "", fiber.Map{"refresh_token": tokens.RefreshToken})
+	assert.Equal(t, 401, code)
+	code, resp = h.request("POST", "/api/login", "", fiber.Map{"email": "alfan@example.com", "password": "Secret123!"})
+	assert.Equal(t, 403, code)
+	assert.Equal(t, "account suspended", resp.errorMessage(t))
+
+	code, resp = h.request("GET", "/api/admin/users?status=suspended", adminToken, nil)
+	require.Equal(t, 200, code)
+	var users []adminUserResult
+	resp.decode(t, &users)
+	require.Len(t, users, 1)
+	assert.Equal(t, userID, users[0].ID)
+
+	code, resp = h.request("DELETE", adminUserPath(userID, "/suspend"), adminToken, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+	resp.decode(t, &user)
+	assert.Nil(t, user.SuspendedAt)
+
+	code, _ = h.request("GET", "/api/users/", accessToken.Token, nil)
+	assert.Equal(t, 200, code)
+	h.login("alfan@example.com", "Secret123!")
+}
+
+func TestSuspensionSurvivesProfileWriteInFlight(t *testing.T) {
+	h := newHarness(t)
+	adminToken := h.newAdmin()
+	userID, userToken := h.newUser("alfan")
+
+	// The admin suspends the user while their avatar is still uploading.
+	h.imageStore.onUpload = func() {
+		code, resp := h.request("POST", adminUserPath(userID, "/suspend"), adminToken, fiber.Map{"reason": "spam"})
+		require.Equal(t, 200, code, string(resp.Data))
+	}
+	code, resp := h.multipart("PUT", "/api/users/image", userToken, nil, "avatar.png")
+	require.Equal(t, 200, code, string(resp.Data))
+
+	var user entity.User
+	require.NoError(t, h.db.Take(&user, userID).Error)
+	assert.True(t, user.Suspended())
+	assert.Equal(t, "https://images.test/avatar.png", user.ImageUrl)
+
+	code, _ = h.request("GET", "/api/users/", userToken, nil)
+	assert.Equal(t, 401, code)
+}
+
+func TestAdminForcePasswordReset(t *testing.T) {
+	h := newHarness(t)
+	adminToken := h.newAdmin()
+	userID, userToken := h.newUser("alfan")
+
+	code, resp := h.request("POST", adminUserPath(userID, "/password-reset"), adminToken, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+	var user adminUserResult
+	resp.decode(t, &user)
+	assert.True(t, user.PasswordResetRequired)
+
+	code, _ = h.request("GET", "/api/users/", userToken, nil)
+	assert.Equal(t, 401, code)
+
+	code, resp = h.request("POST", "/api/login", "", fiber.Map{"email": "alfan@example.com", "password": "Secret123!"})
+	assert.Equal(t, 403, code)
+	assert.Equal(t, "a password reset is required, check your email", resp.errorMessage(t))
+
+	code, resp = h.request("POST", "/api/password/reset", "", fiber.Map{
+		"token":    h.mailToken("alfan@example.com"),
+		"password": "Changed123!",
+	})
+	require.Equal(t, 200, code, string(resp.Data))
+
+	h.login("alfan@example.com", "Changed123!")
+
+	code, _ = h.request("POST", adminUserPath(9999, "/password-reset"), adminToken, nil)
+	assert.Equal(t, 404, code)
+}
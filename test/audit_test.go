@@ -0,0 +1,143 @@
+package test
+
+import (
+	"encoding/json"
+	"meals-app/model/entity"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditEventResult struct {
+	Action     string `json:"action"`
+	Outcome    string `json:"outcome"`
+	ActorID    *int   `json:"actor_id"`
+	TargetType string `json:"target_type"`
+	TargetID   *int   `json:"target_id"`
+	IPAddress  string `json:"ip_address"`
+	Detail     string `json:"detail"`
+}
+
+type securityActivityResult struct {
+	Action    string `json:"action"`
+	Outcome   string `json:"outcome"`
+	ByAdmin   bool   `json:"by_admin"`
+	IPAddress string `json:"ip_address"`
+	UserAgent string `json:"user_agent"`
+	Detail    string `json:"detail"`
+}
+
+func (h *harness) auditEvents(token string, query url.Values) ([]auditEventResult, pageMetaResult) {
+	h.t.Helper()
+
+	code, resp := h.request("GET", "/api/admin/audit-events?"+query.Encode(), token, nil)
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var events []auditEventResult
+	resp.decode(h.t, &events)
+	var meta pageMetaResult
+	require.NoError(h.t, json.Unmarshal(resp.Meta, &meta))
+	return events, meta
+}
+
+func TestAuditLogRecordsLogins(t *testing.T) {
+	h := newHarness(t)
+	adminToken := h.newAdmin()
+	userID, _ := h.newUser("alfan")
+
+	code, _ := h.request("POST", "/api/login", "", fiber.Map{"email": "alfan@example.com", "password": "Wrong123!"})
+	require.Equal(t, 401, code)
+	code, _ = h.request("POST", "/api/login", "", fiber.Map{"email": "nobody@example.com", "password": "Wrong123!"})
+	require.Equal(t, 401, code)
+
+	events, meta := h.auditEvents(adminToken, url.Values{"action": {entity.AuditLogin}, "outcome": {entity.AuditFailure}})
+	require.Len(t, events, 2)
+	assert.EqualValues(t, 2, meta.Total)
+	assert.Equal(t, "invalid credentials for nobody@example.com", events[0].Detail)
+	assert.Nil(t, events[0].TargetID)
+	require.NotNil(t, events[1].TargetID)
+	assert.Equal(t, userID, *events[1].TargetID)
+	assert.Nil(t, events[1].ActorID)
+	assert.NotEmpty(t, events[1].IPAddress)
+
+	events, _ = h.auditEvents(adminToken, url.Values{"action": {entity.AuditLogin}, "target_id": {"2"}, "outcome": {entity.AuditSuccess}})
+	require.Len(t, events, 1)
+	assert.Equal(t, userID, *events[0].ActorID)
+
+	code, _ = h.request("GET", "/api/admin/audit-events?from=yesterday", adminToken, nil)
+	assert.Equal(t, 422, code)
+}
+
+func TestSecurityActivity(t *testing.T) {
+	h := newHarness(t)
+	adminToken := h.newAdmin()
+	userID, token := h.newUser("alfan")
+
+	code, _ := h.request("PUT", "/api/users/change-password", token, fiber.Map{"current_password": "Wrong123!", "password": "Changed123!"})
+	require.Equal(t, 400, code)
+	code, resp := h.request("PUT", "/api/users/change-password", token, fiber.Map{"current_password": "Secret123!", "password": "Changed123!"})
+	require.Equal(t, 200, code, string(resp.Data))
+	token = h.login("alfan@example.com", "Changed123!")
+
+	code, resp = h.request("PUT", "/api/users/", token, fiber.Map{"email": "alfan@other.example.com"})
+	require.Equal(t, 200, code, string(resp.Data))
+
+	code, resp = h.request("PUT", adminUserPath(userID, "/role"), adminToken, fiber.Map{"role": entity.RoleModerator})
+	require.Equal(t, 200, code, string(resp.Data))
+
+	code, resp = h.request("GET", "/api/users/security-activity", token, nil)
+	require.Equal(t, 401, code, "changing the email signs the user out")
+	token = h.login("alfan@other.example.com", "Changed123!")
+
+	code, resp = h.request("GET", "/api/users/security-activity", token, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+	var events []securityActivityResult
+	resp.decode(t, &events)
+
+	var actions []string
+	for _, event := range events {
+		actions = append(actions, event.Action+":"+event.Outcome)
+	}
+	assert.Equal(t, []string{
+		"user.login:success",
+		"admin.role_change:success",
+		"user.email_change:success",
+		"user.login:success",
+		"user.password_change:success",
+		"user.password_change:failure",
+		"user.login:success",
+	}, actions)
+	assert.Equal(t, "user to moderator", events[1].Detail)
+	assert.True(t, events[1].ByAdmin)
+	assert.Empty(t, events[1].IPAddress)
+	assert.Empty(t, events[1].UserAgent)
+	assert.NotContains(t, string(resp.Data), "actor_id")
+	assert.False(t, events[0].ByAdmin)
+	assert.NotEmpty(t, events[0].IPAddress)
+	assert.Equal(t, "alfan@example.com to alfan@other.example.com", events[2].Detail)
+
+	code, _ = h.request("GET", "/api/admin/audit-events", token, nil)
+	assert.Equal(t, 403, code)
+}
+
+func TestAuditLogRecordsAdminMealDeletion(t *testing.T) {
+	h := newHarness(t)
+	adminToken := h.newAdmin()
+	_, token := h.newUser("alfan")
+	own := h.createMeal(token, "soto")
+	other := h.createMeal(token, "fried rice")
+
+	code, _ := h.request("DELETE", mealPath(own.ID, ""), token, nil)
+	require.Equal(t, 200, code)
+	code, _ = h.request("DELETE", mealPath(other.ID, ""), adminToken, nil)
+	require.Equal(t, 200, code)
+
+	events, _ := h.auditEvents(adminToken, url.Values{"target_type": {entity.AuditTargetMeal}})
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.AuditMealDelete, events[0].Action)
+	assert.Equal(t, other.ID, *events[0].TargetID)
+	assert.Equal(t, `"fried rice" owned by user 2`, events[0].Detail)
+}
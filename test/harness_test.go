@@ -0,0 +1,410 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"meals-app/config"
+	"meals-app/database"
+	"meals-app/model/entity"
+	"meals-app/router"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeImageStore records uploads in memory instead of talking to Cloudinary.
+// onUpload, when set, runs during every upload, standing in for whatever
+// happens while a real one is in flight, and uploadErr fails them.
+type fakeImageStore struct {
+	mu        sync.Mutex
+	uploads   map[string][]byte
+	pings     int
+	onUpload  func()
+	uploadErr error
+}
+
+func newFakeImageStore() *fakeImageStore {
+	return &fakeImageStore{uploads: map[string][]byte{}}
+}
+
+func (store *fakeImageStore) Upload(ctx context.Context, file io.Reader, filename string) (string, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	if store.onUpload != nil {
+		store.onUpload()
+	}
+	if store.uploadErr != nil {
+		return "", store.uploadErr
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.uploads[filename] = content
+
+	return "https://images.test/" + filepath.Base(filename), nil
+}
+
+func (store *fakeImageStore) Ping(ctx context.Context) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.pings++
+	return nil
+}
+
+type harness struct {
+	t          *testing.T
+	app        *fiber.App
+	db         *gorm.DB
+	cfg        *config.Config
+	imageStore *fakeImageStore
+	outboxDir  string
+}
+
+func newTestConfig() *config.Config {
+	return &config.Config{
+		App: config.AppConfig{
+			Port:            3000,
+			BaseURL:         "http://localhost:3000",
+			ShutdownTimeout: time.Second,
+		},
+		Database: config.DatabaseConfig{
+			Driver: "sqlite",
+			URL:    "file::memory:",
+		},
+		JWT: config.JWTConfig{
+			Algorithm:     "HS256",
+			Secret:        "test-secret",
+			Expiry:        time.Hour,
+			RefreshExpiry: 24 * time.Hour,
+		},
+		Storage: config.StorageConfig{
+			Driver: "local",
+		},
+		Mail: config.MailConfig{
+			Driver: "outbox",
+			From:   "Meals App <no-reply@example.com>",
+		},
+		Password: config.PasswordConfig{
+			MinLength:    8,
+			RequireUpper: true,
+			RequireLower: true,
+			RequireDigit: true,
+		},
+		Login: config.LoginConfig{
+			MaxAttempts:     3,
+			IPMaxAttempts:   10,
+			LockoutDuration: 15 * time.Minute,
+		},
+		TwoFactor: config.TwoFactorConfig{
+			Issuer:          "Meals App",
+			ChallengeExpiry: 5 * time.Minute,
+			RecoveryCodes:   10,
+		},
+		Account: config.AccountConfig{
+			VerificationExpiry:  time.Hour,
+			PasswordResetExpiry: time.Hour,
+			PasswordResetURL:    "http://app.test/reset-password",
+			DeletionGracePeriod: 24 * time.Hour,
+			DeletionTokenExpiry: time.Hour,
+			DeletedRecipes:      "anonymize",
+		},
+	}
+}
+
+// newHarness builds the application exactly as main does, backed by a fresh
+// in-memory SQLite database with every migration applied. Mail goes to an
+// outbox directory that tests can read back. configure may adjust the
+// test configuration before the app is built.
+func newHarness(t *testing.T, configure ...func(cfg *config.Config)) *harness {
+	t.Helper()
+
+	cfg := newTestConfig()
+	for _, apply := range configure {
+		apply(cfg)
+	}
+
+	dialector, err := database.NewDialector(cfg.Database.Driver, cfg.Database.URL)
+	require.NoError(t, err)
+	db, err := gorm.Open(dialector, &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// Every connection to :memory: opens its own empty database, so keep a
+	// single connection for the lifetime of the test.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	_, err = database.MigrateUp(db)
+	require.NoError(t, err)
+
+	imageStore := newFakeImageStore()
+	cfg.Mail.OutboxDir = t.TempDir()
+
+	keySet, err := config.NewKeySet(cfg.JWT)
+	require.NoError(t, err)
+
+	return &harness{
+		t:          t,
+		app:        router.NewApp(cfg, db, imageStore, config.NewMailer(cfg.Mail), keySet),
+		db:         db,
+		cfg:        cfg,
+		imageStore: imageStore,
+		outboxDir:  cfg.Mail.OutboxDir,
+	}
+}
+
+type response struct {
+	Code   int             `json:"code"`
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data"`
+	Meta   json.RawMessage `json:"meta"`
+}
+
+func (r response) decode(t *testing.T, target any) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(r.Data, target))
+}
+
+func (r response) errorMessage(t *testing.T) string {
+	t.Helper()
+	var data struct {
+		Error string `json:"error"`
+	}
+	r.decode(t, &data)
+	return data.Error
+}
+
+func (h *harness) do(req *http.Request) (int, response) {
+	h.t.Helper()
+
+	resp, err := h.app.Test(req, -1)
+	require.NoError(h.t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(h.t, err)
+
+	var result response
+	if len(body) > 0 && resp.Header.Get(fiber.HeaderContentType) == fiber.MIMEApplicationJSON {
+		require.NoError(h.t, json.Unmarshal(body, &result), string(body))
+	}
+
+	return resp.StatusCode, result
+}
+
+func (h *harness) request(method string, path string, token string, body any) (int, response) {
+	h.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		require.NoError(h.t, err)
+		reader = bytes.NewReader(content)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+
+	return h.do(req)
+}
+
+// multipart sends fields (repeating keys for slices) plus an optional image.
+func (h *harness) multipart(method string, path string, token string, fields map[string][]string, imageName string) (int, response) {
+	h.t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, values := range fields {
+		for _, value := range values {
+			require.NoError(h.t, writer.WriteField(key, value))
+		}
+	}
+	if imageName != "" {
+		part, err := writer.CreateFormFile("image", imageName)
+		require.NoError(h.t, err)
+		_, err = part.Write([]byte("fake image content"))
+		require.NoError(h.t, err)
+	}
+	require.NoError(h.t, writer.Close())
+
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+
+	return h.do(req)
+}
+
+func (h *harness) register(username string, email string, password string) int {
+	h.t.Helper()
+
+	code, resp := h.request("POST", "/api/register", "", fiber.Map{
+		"username": username,
+		"email":    email,
+		"password": password,
+	})
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var user struct {
+		ID int `json:"id"`
+	}
+	resp.decode(h.t, &user)
+	return user.ID
+}
+
+type tokenResult struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (h *harness) loginTokens(email string, password string) tokenResult {
+	h.t.Helper()
+
+	code, resp := h.request("POST", "/api/login", "", fiber.Map{
+		"email":    email,
+		"password": password,
+	})
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var tokens tokenResult
+	resp.decode(h.t, &tokens)
+	return tokens
+}
+
+func (h *harness) login(email string, password string) string {
+	h.t.Helper()
+	return h.loginTokens(email, password).AccessToken
+}
+
+// mails returns the messages delivered to the given address, oldest first.
+func (h *harness) mails(to string) []string {
+	h.t.Helper()
+
+	entries, err := os.ReadDir(h.outboxDir)
+	require.NoError(h.t, err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	var messages []string
+	for _, name := range names {
+		content, err := os.ReadFile(filepath.Join(h.outboxDir, name))
+		require.NoError(h.t, err)
+		if bytes.Contains(content, []byte("To: "+to+"\r\n")) {
+			messages = append(messages, string(content))
+		}
+	}
+	return messages
+}
+
+var mailTokenPattern = regexp.MustCompile(`token=([^\s]+)`)
+
+// mailToken returns the token from the latest link mailed to the address.
+func (h *harness) mailToken(to string) string {
+	h.t.Helper()
+
+	messages := h.mails(to)
+	require.NotEmpty(h.t, messages, "no mail sent to %s", to)
+
+	match := mailTokenPattern.FindStringSubmatch(messages[len(messages)-1])
+	require.NotNil(h.t, match)
+
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(h.t, err)
+	return token
+}
+
+func (h *harness) verifyEmail(email string) {
+	h.t.Helper()
+
+	code, resp := h.request("GET", "/api/verify-email?token="+url.QueryEscape(h.mailToken(email)), "", nil)
+	require.Equal(h.t, 200, code, string(resp.Data))
+}
+
+// newUser registers and verifies a user and returns its ID and a fresh
+// access token.
+func (h *harness) newUser(name string) (int, string) {
+	h.t.Helper()
+
+	email := name + "@example.com"
+	id := h.register(name, email, "Secret123!")
+	h.verifyEmail(email)
+	return id, h.login(email, "Secret123!")
+}
+
+func (h *harness) setRole(userID int, role string) {
+	h.t.Helper()
+	require.NoError(h.t, h.db.Model(&entity.User{}).Where("id = ?", userID).Update("role", role).Error)
+}
+
+func mealFields(name string) map[string][]string {
+	return map[string][]string{
+		"name":            {name},
+		"category":        {"food"},
+		"duration":        {"30m"},
+		"complexity":      {"simple"},
+		"affordability":   {"affordable"},
+		"is_gluten_free":  {"true"},
+		"is_lactose_free": {"false"},
+		"is_vegan":        {"true"},
+		"ingredients[]":   {"rice", "egg"},
+		"steps[]":         {"cook rice", "fry egg", "mix"},
+	}
+}
+
+type mealResult struct {
+	ID          int      `json:"id"`
+	UserId      int      `json:"user_id"`
+	Name        string   `json:"name"`
+	ImageUrl    string   `json:"image_url"`
+	IsVegan     bool     `json:"is_vegan"`
+	Ingredients []string `json:"ingredients"`
+	Steps       []string `json:"steps"`
+}
+
+func (h *harness) createMeal(token string, name string) mealResult {
+	h.t.Helper()
+
+	code, resp := h.multipart("POST", "/api/meals", token, mealFields(name), name+".jpg")
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var meal mealResult
+	resp.decode(h.t, &meal)
+	return meal
+}
+
+func mealPath(mealID int, suffix string) string {
+	return fmt.Sprintf("/api/meals/%d%s", mealID, suffix)
+}
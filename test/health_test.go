@@ -0,0 +1,21 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadinessReusesImageStoreCheck(t *testing.T) {
+	h := newHarness(t)
+
+	for i := 0; i < 3; i++ {
+		code, resp := h.request("GET", "/readyz", "", nil)
+		require.Equal(t, 200, code, string(resp.Data))
+	}
+
+	h.imageStore.mu.Lock()
+	defer h.imageStore.mu.Unlock()
+	assert.Equal(t, 1, h.imageStore.pings)
+}
@@ -0,0 +1,39 @@
+package test
+
+import (
+	"context"
+	"meals-app/exception"
+	"meals-app/storage"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalImageStoreKeepsUploadsApart(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewLocalImageStore(dir, "/images", "http://localhost:3000")
+
+	first, err := store.Upload(context.Background(), strings.NewReader("first"), "photo.jpg")
+	require.NoError(t, err)
+	second, err := store.Upload(context.Background(), strings.NewReader("second"), "../photo.JPG")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.True(t, strings.HasPrefix(first, "http://localhost:3000/images/"))
+	assert.Equal(t, ".jpg", path.Ext(second))
+
+	content, err := os.ReadFile(filepath.Join(dir, path.Base(first)))
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(content))
+	content, err = os.ReadFile(filepath.Join(dir, path.Base(second)))
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+
+	_, err = store.Upload(context.Background(), strings.NewReader("script"), "photo.html")
+	assert.ErrorIs(t, err, exception.ErrInvalidImageFormat)
+}
@@ -0,0 +1,155 @@
+package test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"meals-app/config"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	private        crypto.Signer
+	privateKeyFile string
+	publicKeyFile  string
+}
+
+func newTestKey(t *testing.T, algorithm string) testKey {
+	t.Helper()
+
+	var private crypto.Signer
+	var err error
+	if algorithm == "RS256" {
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	} else {
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	key := testKey{
+		private:        private,
+		privateKeyFile: filepath.Join(dir, "private.pem"),
+		publicKeyFile:  filepath.Join(dir, "public.pem"),
+	}
+	require.NoError(t, os.WriteFile(key.privateKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600))
+	require.NoError(t, os.WriteFile(key.publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644))
+	return key
+}
+
+type jwksResult struct {
+	Keys []struct {
+		KeyType   string `json:"kty"`
+		KeyID     string `json:"kid"`
+		Algorithm string `json:"alg"`
+		Curve     string `json:"crv"`
+	} `json:"keys"`
+}
+
+func (h *harness) jwks() jwksResult {
+	h.t.Helper()
+
+	resp, err := h.app.Test(httptest.NewRequest("GET", "/.well-known/jwks.json", nil), -1)
+	require.NoError(h.t, err)
+	defer resp.Body.Close()
+	require.Equal(h.t, 200, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(h.t, err)
+
+	var jwks jwksResult
+	require.NoError(h.t, json.Unmarshal(body, &jwks))
+	return jwks
+}
+
+func tokenKeyID(t *testing.T, token string) string {
+	t.Helper()
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestAsymmetricSigning(t *testing.T) {
+	for _, tt := range []struct{ algorithm, keyType string }{{"RS256", "RSA"}, {"EdDSA", "OKP"}} {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			key := newTestKey(t, tt.algorithm)
+			h := newHarness(t, func(cfg *config.Config) {
+				cfg.JWT.Algorithm = tt.algorithm
+				cfg.JWT.Secret = ""
+				cfg.JWT.Keys = []config.JWTKeyConfig{{ID: "current", PrivateKeyFile: key.privateKeyFile}}
+			})
+
+			_, token := h.newUser("alfan")
+			assert.Equal(t, "current", tokenKeyID(t, token))
+
+			code, _ := h.request("GET", "/api/users", token, nil)
+			assert.Equal(t, 200, code)
+
+			jwks := h.jwks()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, "current", jwks.Keys[0].KeyID)
+			assert.Equal(t, tt.keyType, jwks.Keys[0].KeyType)
+			assert.Equal(t, tt.algorithm, jwks.Keys[0].Algorithm)
+		})
+	}
+}
+
+func TestKeyRotationKeepsOldTokensValid(t *testing.T) {
+	current := newTestKey(t, "RS256")
+	previous := newTestKey(t, "RS256")
+	unknown := newTestKey(t, "RS256")
+
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.JWT.Algorithm = "RS256"
+		cfg.JWT.Keys = []config.JWTKeyConfig{
+			{ID: "current", PrivateKeyFile: current.privateKeyFile},
+			{ID: "previous", PublicKeyFile: previous.publicKeyFile},
+		}
+	})
+	_, token := h.newUser("alfan")
+
+	// Re-sign the same claims as if the token had been issued before the
+	// rotation.
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	resign := func(kid string, key crypto.Signer) string {
+		old := jwt.NewWithClaims(jwt.SigningMethodRS256, parsed.Claims)
+		old.Header["kid"] = kid
+		signed, err := old.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	code, _ := h.request("GET", "/api/users", resign("previous", previous.private), nil)
+	assert.Equal(t, 200, code)
+
+	code, _ = h.request("GET", "/api/users", resign("previous", unknown.private), nil)
+	assert.Equal(t, 401, code)
+
+	assert.Len(t, h.jwks().Keys, 2)
+}
+
+func TestSymmetricKeysAreNotPublished(t *testing.T) {
+	h := newHarness(t)
+
+	assert.Empty(t, h.jwks().Keys)
+}
@@ -0,0 +1,166 @@
+package test
+
+import (
+	"fmt"
+	"meals-app/config"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (h *harness) tryLogin(email string, password string) (int, response) {
+	h.t.Helper()
+	return h.request("POST", "/api/login", "", fiber.Map{"email": email, "password": password})
+}
+
+func TestLoginUnknownEmailLooksLikeWrongPassword(t *testing.T) {
+	h := newHarness(t)
+	h.newUser("alfan")
+
+	wrongCode, wrongResp := h.tryLogin("alfan@example.com", "Wrong123!")
+	unknownCode, unknownResp := h.tryLogin("nobody@example.com", "Wrong123!")
+
+	assert.Equal(t, 401, wrongCode)
+	assert.Equal(t, wrongCode, unknownCode)
+	assert.Equal(t, wrongResp.Status, unknownResp.Status)
+	assert.Equal(t, wrongResp.errorMessage(t), unknownResp.errorMessage(t))
+}
+
+func TestLoginLocksAccountAfterMaxAttempts(t *testing.T) {
+	h := newHarness(t)
+	h.newUser("alfan")
+
+	for i := 0; i < h.cfg.Login.MaxAttempts; i++ {
+		code, _ := h.tryLogin("alfan@example.com", "Wrong123!")
+		require.Equal(t, 401, code)
+	}
+
+	code, resp := h.tryLogin("alfan@example.com", "Secret123!")
+	assert.Equal(t, 429, code)
+	assert.Equal(t, "too many failed login attempts, try again later", resp.errorMessage(t))
+
+	code, _ = h.tryLogin("ALFAN@example.com", "Secret123!")
+	assert.Equal(t, 429, code)
+}
+
+func TestLoginBackoffSetsRetryAfter(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Login.BaseDelay = time.Minute
+	})
+	h.newUser("alfan")
+
+	code, _ := h.tryLogin("alfan@example.com", "Wrong123!")
+	require.Equal(t, 401, code)
+
+	body := `{"email":"alfan@example.com","password":"Secret123!"}`
+	req := httptest.NewRequest("POST", "/api/login", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := h.app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, 429, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func TestLoginLocksIPAddress(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Login.IPMaxAttempts = 2
+	})
+	h.newUser("alfan")
+
+	h.tryLogin("first@example.com", "Wrong123!")
+	h.tryLogin("second@example.com", "Wrong123!")
+
+	code, _ := h.tryLogin("alfan@example.com", "Secret123!")
+	assert.Equal(t, 429, code)
+}
+
+func TestLoginLockoutHoldsForConcurrentAttempts(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Login.IPMaxAttempts = 100
+	})
+	h.newUser("alfan")
+
+	codes := make(chan int, 20)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(codes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, _ := h.tryLogin("alfan@example.com", "Wrong123!")
+			codes <- code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	counts := map[int]int{}
+	for code := range codes {
+		counts[code]++
+	}
+	assert.Equal(t, map[int]int{401: h.cfg.Login.MaxAttempts, 429: cap(codes) - h.cfg.Login.MaxAttempts}, counts)
+}
+
+func TestSuccessfulLoginsDoNotCountAgainstIPAddress(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Login.IPMaxAttempts = 2
+	})
+	h.newUser("alfan")
+
+	for i := 0; i < 3; i++ {
+		h.login("alfan@example.com", "Secret123!")
+	}
+}
+
+func TestLoginReadsClientIPFromTrustedProxy(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.App.ProxyHeader = "X-Real-IP"
+		cfg.App.TrustedProxies = []string{"0.0.0.0"}
+		cfg.Login.IPMaxAttempts = 1
+	})
+	h.newUser("alfan")
+
+	loginFrom := func(ip string, email string, password string) int {
+		body := fmt.Sprintf(`{"email":%q,"password":%q}`, email, password)
+		req := httptest.NewRequest("POST", "/api/login", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set("X-Real-IP", ip)
+		code, _ := h.do(req)
+		return code
+	}
+
+	assert.Equal(t, 401, loginFrom("203.0.113.1", "nobody@example.com", "Wrong123!"))
+	assert.Equal(t, 429, loginFrom("203.0.113.1", "alfan@example.com", "Secret123!"))
+	assert.Equal(t, 200, loginFrom("203.0.113.2", "alfan@example.com", "Secret123!"))
+}
+
+func TestAdminUnlocksAccount(t *testing.T) {
+	h := newHarness(t)
+	userID, userToken := h.newUser("alfan")
+	adminID, adminToken := h.newUser("admin")
+	h.setRole(adminID, "admin")
+
+	for i := 0; i < h.cfg.Login.MaxAttempts; i++ {
+		h.tryLogin("alfan@example.com", "Wrong123!")
+	}
+
+	path := fmt.Sprintf("/api/admin/users/%d/unlock", userID)
+	code, _ := h.request("POST", path, userToken, nil)
+	assert.Equal(t, 403, code)
+
+	code, _ = h.request("POST", path, adminToken, nil)
+	require.Equal(t, 200, code)
+
+	code, _ = h.tryLogin("alfan@example.com", "Secret123!")
+	assert.Equal(t, 200, code)
+
+	code, _ = h.request("POST", "/api/admin/users/9999/unlock", adminToken, nil)
+	assert.Equal(t, 404, code)
+}
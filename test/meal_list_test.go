@@ -0,0 +1,226 @@
+package test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mealSummaryResult struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	FavoriteCount int64    `json:"favorite_count"`
+	Ingredients   []string `json:"ingredients"`
+	Steps         []string `json:"steps"`
+}
+
+type facetCountResult struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type mealListMetaResult struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+	Total      int64   `json:"total"`
+	Facets     struct {
+		Category      []facetCountResult `json:"category"`
+		Complexity    []facetCountResult `json:"complexity"`
+		Affordability []facetCountResult `json:"affordability"`
+		Diet          map[string]int64   `json:"diet"`
+	} `json:"facets"`
+}
+
+func (h *harness) listMeals(token string, query url.Values) ([]mealSummaryResult, mealListMetaResult) {
+	h.t.Helper()
+
+	code, resp := h.request("GET", "/api/meals?"+query.Encode(), token, nil)
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var meals []mealSummaryResult
+	resp.decode(h.t, &meals)
+	var meta mealListMetaResult
+	require.NoError(h.t, json.Unmarshal(resp.Meta, &meta))
+	return meals, meta
+}
+
+// allMealNames follows next_cursor until the last page and returns the
+// names in the order they were listed.
+func (h *harness) allMealNames(token string, query url.Values) []string {
+	h.t.Helper()
+
+	var names []string
+	for pages := 0; pages < 10; pages++ {
+		meals, meta := h.listMeals(token, query)
+		for _, meal := range meals {
+			names = append(names, meal.Name)
+		}
+		if meta.NextCursor == nil {
+			return names
+		}
+		query.Set("cursor", *meta.NextCursor)
+	}
+	h.t.Fatal("listing did not end")
+	return nil
+}
+
+func TestListMealsPaginatesNewestFirst(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	for _, name := range []string{"soup", "salad", "stew", "pie", "rice"} {
+		h.createMeal(token, name)
+	}
+
+	meals, meta := h.listMeals(token, url.Values{"limit": {"2"}})
+	require.Len(t, meals, 2)
+	assert.Equal(t, 2, meta.Limit)
+	require.NotNil(t, meta.NextCursor)
+	assert.Equal(t, []string{"rice", "egg"}, meals[0].Ingredients)
+	assert.Nil(t, meals[0].Steps)
+
+	names := h.allMealNames(token, url.Values{"limit": {"2"}})
+	assert.Equal(t, []string{"rice", "pie", "stew", "salad", "soup"}, names)
+
+	_, meta = h.listMeals(token, url.Values{})
+	assert.Equal(t, 20, meta.Limit)
+	assert.Nil(t, meta.NextCursor)
+}
+
+func TestListMealsSorted(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	_, fanToken := h.newUser("fan")
+	soup := h.createMeal(token, "soup")
+	h.createMeal(token, "apple pie")
+	stew := h.createMeal(token, "stew")
+	h.createMeal(token, "noodles")
+
+	for _, favorite := range []struct {
+		token  string
+		mealID int
+	}{{token, stew.ID}, {fanToken, stew.ID}, {fanToken, soup.ID}} {
+		code, _ := h.request("POST", mealPath(favorite.mealID, "/favorites"), favorite.token, nil)
+		require.Equal(t, 200, code)
+	}
+
+	names := h.allMealNames(token, url.Values{"sort": {"name"}, "limit": {"1"}})
+	assert.Equal(t, []string{"apple pie", "noodles", "soup", "stew"}, names)
+
+	names = h.allMealNames(token, url.Values{"sort": {"favorites"}, "limit": {"1"}})
+	assert.Equal(t, []string{"stew", "soup", "noodles", "apple pie"}, names)
+
+	meals, _ := h.listMeals(token, url.Values{"sort": {"favorites"}})
+	assert.Equal(t, int64(2), meals[0].FavoriteCount)
+}
+
+func TestListMealsIncludesStepsOnRequest(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	h.createMeal(token, "fried rice")
+
+	meals, _ := h.listMeals(token, url.Values{"include": {"steps"}})
+	require.Len(t, meals, 1)
+	assert.Equal(t, []string{"cook rice", "fry egg", "mix"}, meals[0].Steps)
+}
+
+func TestListMealsRejectsBadParameters(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	h.createMeal(token, "soup")
+	h.createMeal(token, "stew")
+
+	_, meta := h.listMeals(token, url.Values{"limit": {"1"}})
+	require.NotNil(t, meta.NextCursor)
+
+	code, _ := h.request("GET", "/api/meals?sort=name&cursor="+*meta.NextCursor, token, nil)
+	assert.Equal(t, 400, code)
+
+	code, _ = h.request("GET", "/api/meals?cursor=not-a-cursor", token, nil)
+	assert.Equal(t, 400, code)
+
+	code, resp := h.request("GET", "/api/meals?limit=500", token, nil)
+	assert.Equal(t, 422, code)
+	assert.Equal(t, "limit", resp.fields(t)[0].Field)
+
+	code, resp = h.request("GET", "/api/meals?sort=random", token, nil)
+	assert.Equal(t, 422, code)
+	assert.Equal(t, "sort", resp.fields(t)[0].Field)
+}
+
+// createMealWith creates a meal whose fields differ from mealFields by the
+// given values.
+func (h *harness) createMealWith(token string, name string, fields map[string]string) mealResult {
+	h.t.Helper()
+
+	form := mealFields(name)
+	for key, value := range fields {
+		form[key] = []string{value}
+	}
+	code, resp := h.multipart("POST", "/api/meals", token, form, name+".jpg")
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var meal mealResult
+	resp.decode(h.t, &meal)
+	return meal
+}
+
+func createFacetedMeals(h *harness, token string) {
+	h.createMealWith(token, "pancakes", map[string]string{
+		"category": "breakfast", "complexity": "simple", "affordability": "affordable",
+		"is_gluten_free": "false", "is_lactose_free": "false", "is_vegan": "false",
+	})
+	h.createMealWith(token, "porridge", map[string]string{
+		"category": "breakfast", "complexity": "simple", "affordability": "affordable",
+		"is_gluten_free": "false", "is_lactose_free": "true", "is_vegan": "true",
+	})
+	h.createMealWith(token, "curry", map[string]string{
+		"category": "dinner", "complexity": "challenging", "affordability": "pricey",
+		"is_gluten_free": "true", "is_lactose_free": "false", "is_vegan": "true",
+	})
+	h.createMealWith(token, "steak", map[string]string{
+		"category": "dinner", "complexity": "hard", "affordability": "luxurious",
+		"is_gluten_free": "true", "is_lactose_free": "true", "is_vegan": "false",
+	})
+}
+
+func TestListMealsFilters(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	createFacetedMeals(h, token)
+
+	names := h.allMealNames(token, url.Values{"category": {"breakfast", "dinner"}, "diet": {"vegan"}, "sort": {"name"}})
+	assert.Equal(t, []string{"curry", "porridge"}, names)
+
+	names = h.allMealNames(token, url.Values{"diet": {"vegan", "gluten_free"}})
+	assert.Equal(t, []string{"curry"}, names)
+
+	names = h.allMealNames(token, url.Values{"complexity": {"hard", "simple"}, "affordability": {"affordable"}, "sort": {"name"}})
+	assert.Equal(t, []string{"pancakes", "porridge"}, names)
+
+	names = h.allMealNames(token, url.Values{"category": {"lunch"}})
+	assert.Empty(t, names)
+
+	code, _ := h.request("GET", "/api/meals?diet=keto", token, nil)
+	assert.Equal(t, 422, code)
+}
+
+func TestListMealsFacets(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	createFacetedMeals(h, token)
+
+	_, meta := h.listMeals(token, url.Values{"category": {"dinner"}, "limit": {"1"}})
+	assert.Equal(t, int64(2), meta.Total)
+	// The category counts ignore the category filter itself.
+	assert.Equal(t, []facetCountResult{{"breakfast", 2}, {"dinner", 2}}, meta.Facets.Category)
+	assert.Equal(t, []facetCountResult{{"challenging", 1}, {"hard", 1}}, meta.Facets.Complexity)
+	assert.Equal(t, map[string]int64{"gluten_free": 2, "lactose_free": 1, "vegan": 1}, meta.Facets.Diet)
+
+	_, meta = h.listMeals(token, url.Values{"diet": {"vegan"}})
+	assert.Equal(t, int64(2), meta.Total)
+	assert.Equal(t, []facetCountResult{{"breakfast", 1}, {"dinner", 1}}, meta.Facets.Category)
+	assert.Equal(t, map[string]int64{"gluten_free": 1, "lactose_free": 1, "vegan": 2}, meta.Facets.Diet)
+}
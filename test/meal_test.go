@@ -0,0 +1,207 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMeal(t *testing.T) {
+	h := newHarness(t)
+	userID, token := h.newUser("alfan")
+
+	meal := h.createMeal(token, "fried rice")
+	assert.Equal(t, userID, meal.UserId)
+	assert.Equal(t, "fried rice", meal.Name)
+	assert.Equal(t, "https://images.test/fried rice.jpg", meal.ImageUrl)
+	assert.True(t, meal.IsVegan)
+	assert.Equal(t, []string{"rice", "egg"}, meal.Ingredients)
+	assert.Equal(t, []string{"cook rice", "fry egg", "mix"}, meal.Steps)
+}
+
+func TestCreateMealInvalidBoolean(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	fields := mealFields("fried rice")
+	fields["is_vegan"] = []string{"maybe"}
+
+	code, resp := h.multipart("POST", "/api/meals", token, fields, "fried-rice.jpg")
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "is_vegan must be a boolean", resp.errorMessage(t))
+}
+
+func TestCreateMealWithoutImage(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, _ := h.multipart("POST", "/api/meals", token, mealFields("fried rice"), "")
+	assert.Equal(t, 400, code)
+}
+
+func TestGetMeal(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	meal := h.createMeal(token, "fried rice")
+
+	code, resp := h.request("GET", mealPath(meal.ID, ""), token, nil)
+	require.Equal(t, 200, code)
+
+	var found mealResult
+	resp.decode(t, &found)
+	assert.Equal(t, meal, found)
+}
+
+func TestGetMealNotFound(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, resp := h.request("GET", mealPath(999, ""), token, nil)
+	assert.Equal(t, 404, code)
+	assert.Equal(t, "meal recipe not found", resp.errorMessage(t))
+}
+
+func TestGetMealInvalidID(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, _ := h.request("GET", "/api/meals/abc", token, nil)
+	assert.Equal(t, 400, code)
+}
+
+func TestSearchMealsByName(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	h.createMeal(token, "fried rice")
+	h.createMeal(token, "chicken soup")
+
+	code, resp := h.request("GET", "/api/meals?name=RICE", token, nil)
+	require.Equal(t, 200, code)
+
+	var meals []mealResult
+	resp.decode(t, &meals)
+	require.Len(t, meals, 1)
+	assert.Equal(t, "fried rice", meals[0].Name)
+}
+
+func TestUpdateMealByOwner(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	meal := h.createMeal(token, "fried rice")
+
+	code, resp := h.request("PUT", mealPath(meal.ID, ""), token, fiber.Map{
+		"name":        "nasi goreng",
+		"is_vegan":    "false",
+		"ingredients": []string{"rice", "egg", "kecap"},
+	})
+	require.Equal(t, 200, code, string(resp.Data))
+
+	var updated mealResult
+	resp.decode(t, &updated)
+	assert.Equal(t, "nasi goreng", updated.Name)
+	assert.False(t, updated.IsVegan)
+	assert.Equal(t, []string{"rice", "egg", "kecap"}, updated.Ingredients)
+	assert.Equal(t, meal.Steps, updated.Steps)
+}
+
+func TestUpdateMealByOtherUserIsForbidden(t *testing.T) {
+	h := newHarness(t)
+	_, ownerToken := h.newUser("owner")
+	_, otherToken := h.newUser("other")
+	meal := h.createMeal(ownerToken, "fried rice")
+
+	code, _ := h.request("PUT", mealPath(meal.ID, ""), otherToken, fiber.Map{"name": "stolen"})
+	assert.Equal(t, 403, code)
+
+	code, _ = h.multipart("PUT", mealPath(meal.ID, "/image"), otherToken, nil, "stolen.jpg")
+	assert.Equal(t, 403, code)
+}
+
+func TestUpdateMealImage(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	meal := h.createMeal(token, "fried rice")
+
+	code, resp := h.multipart("PUT", mealPath(meal.ID, "/image"), token, nil, "new.png")
+	require.Equal(t, 200, code)
+
+	var updated mealResult
+	resp.decode(t, &updated)
+	assert.Equal(t, "https://images.test/new.png", updated.ImageUrl)
+}
+
+func TestDeleteMeal(t *testing.T) {
+	h := newHarness(t)
+	_, ownerToken := h.newUser("owner")
+	_, otherToken := h.newUser("other")
+	meal := h.createMeal(ownerToken, "fried rice")
+
+	code, _ := h.request("POST", mealPath(meal.ID, "/favorites"), otherToken, nil)
+	require.Equal(t, 200, code)
+
+	code, _ = h.request("DELETE", mealPath(meal.ID, ""), otherToken, nil)
+	assert.Equal(t, 403, code)
+
+	code, _ = h.request("DELETE", mealPath(meal.ID, ""), ownerToken, nil)
+	assert.Equal(t, 200, code)
+
+	code, _ = h.request("GET", mealPath(meal.ID, ""), ownerToken, nil)
+	assert.Equal(t, 404, code)
+
+	// Ingredients, steps and favorites go with the meal.
+	for _, table := range []string{"meal_ingredients", "meal_recipe_steps", "favorite_user_meal"} {
+		var count int64
+		require.NoError(t, h.db.Table(table).Where("meal_recipe_id = ?", meal.ID).Count(&count).Error)
+		assert.Zero(t, count, table)
+	}
+}
+
+func TestAdminCanDeleteAnyMeal(t *testing.T) {
+	h := newHarness(t)
+	_, ownerToken := h.newUser("owner")
+	adminID, adminToken := h.newUser("admin")
+	h.setRole(adminID, "admin")
+	meal := h.createMeal(ownerToken, "fried rice")
+
+	code, _ := h.request("DELETE", mealPath(meal.ID, ""), adminToken, nil)
+	assert.Equal(t, 200, code)
+}
+
+func TestFavorites(t *testing.T) {
+	h := newHarness(t)
+	_, ownerToken := h.newUser("owner")
+	_, fanToken := h.newUser("fan")
+	meal := h.createMeal(ownerToken, "fried rice")
+
+	code, _ := h.request("DELETE", mealPath(meal.ID, "/favorites"), fanToken, nil)
+	assert.Equal(t, 404, code)
+
+	code, _ = h.request("POST", mealPath(meal.ID, "/favorites"), fanToken, nil)
+	require.Equal(t, 200, code)
+
+	code, resp := h.request("GET", "/api/users/favorites", fanToken, nil)
+	require.Equal(t, 200, code)
+	var favorites []mealResult
+	resp.decode(t, &favorites)
+	require.Len(t, favorites, 1)
+	assert.Equal(t, meal, favorites[0])
+
+	code, _ = h.request("DELETE", mealPath(meal.ID, "/favorites"), fanToken, nil)
+	assert.Equal(t, 200, code)
+
+	code, resp = h.request("GET", "/api/users/favorites", fanToken, nil)
+	require.Equal(t, 200, code)
+	favorites = nil
+	resp.decode(t, &favorites)
+	assert.Empty(t, favorites)
+}
+
+func TestFavoriteUnknownMeal(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, _ := h.request("POST", mealPath(999, "/favorites"), token, nil)
+	assert.Equal(t, 404, code)
+}
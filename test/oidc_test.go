@@ -0,0 +1,344 @@
+package test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"meals-app/config"
+	"meals-app/model/entity"
+	"meals-app/signing"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIssuer is a minimal OpenID Connect provider. Tests play the browser:
+// authorize approves a login URL and returns the code the provider would
+// have redirected back with.
+type mockIssuer struct {
+	server *httptest.Server
+	keySet *signing.KeySet
+
+	mu     sync.Mutex
+	grants map[string]mockGrant
+}
+
+type mockGrant struct {
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keySet, err := signing.NewKeySet("RS256", "mock", []signing.Key{{ID: "mock", PrivateKey: private, PublicKey: &private.PublicKey}})
+	require.NoError(t, err)
+
+	issuer := &mockIssuer{keySet: keySet, grants: map[string]mockGrant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                issuer.server.URL,
+			"authorization_endpoint":                issuer.server.URL + "/authorize",
+			"token_endpoint":                        issuer.server.URL + "/token",
+			"jwks_uri":                              issuer.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(issuer.keySet.JWKS())
+	})
+	mux.HandleFunc("/token", issuer.token)
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (issuer *mockIssuer) authorize(t *testing.T, loginURL string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	parsed, err := url.Parse(loginURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	require.Equal(t, issuer.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.Equal(t, "meals-app", query.Get("client_id"))
+
+	code := query.Get("state") + "-code"
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+	issuer.grants[code] = mockGrant{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		claims:    claims,
+	}
+	return code
+}
+
+func (issuer *mockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	issuer.mu.Lock()
+	grant, ok := issuer.grants[r.PostFormValue("code")]
+	delete(issuer.grants, r.PostFormValue("code"))
+	issuer.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(verifier[:]) != grant.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   issuer.server.URL,
+		"aud":   "meals-app",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": grant.nonce,
+	}
+	for key, value := range grant.claims {
+		claims[key] = value
+	}
+	idToken, err := issuer.keySet.Sign(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+func newOIDCHarness(t *testing.T) (*harness, *mockIssuer) {
+	issuer := newMockIssuer(t)
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.OIDC.StateExpiry = 10 * time.Minute
+		cfg.OIDC.Providers = []config.OIDCProviderConfig{{
+			Name:      "mock",
+			IssuerURL: issuer.server.URL,
+			ClientID:  "meals-app",
+		}}
+	})
+	return h, issuer
+}
+
+func (h *harness) oidcLoginURL(provider string) string {
+	h.t.Helper()
+
+	resp, err := h.app.Test(httptest.NewRequest("GET", "/api/oidc/"+provider+"/login", nil), -1)
+	require.NoError(h.t, err)
+	defer resp.Body.Close()
+	require.Equal(h.t, 302, resp.StatusCode)
+
+	return resp.Header.Get("Location")
+}
+
+func (h *harness) oidcCallback(loginURL string, code string) (int, response) {
+	h.t.Helper()
+
+	parsed, err := url.Parse(loginURL)
+	require.NoError(h.t, err)
+	query := url.Values{"code": {code}, "state": {parsed.Query().Get("state")}}
+
+	return h.request("GET", "/api/oidc/mock/callback?"+query.Encode(), "", nil)
+}
+
+func (h *harness) oidcLogin(issuer *mockIssuer, claims jwt.MapClaims) (int, response) {
+	h.t.Helper()
+
+	loginURL := h.oidcLoginURL("mock")
+	return h.oidcCallback(loginURL, issuer.authorize(h.t, loginURL, claims))
+}
+
+func (h *harness) profile(token string) (int, verifiedResult) {
+	h.t.Helper()
+
+	code, resp := h.request("GET", "/api/users/", token, nil)
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var result struct {
+		ID int `json:"id"`
+		verifiedResult
+	}
+	resp.decode(h.t, &result)
+	return result.ID, result.verifiedResult
+}
+
+func TestOIDCLoginCreatesVerifiedUser(t *testing.T) {
+	h, issuer := newOIDCHarness(t)
+
+	claims := jwt.MapClaims{"sub": "user-1", "email": "dewi@example.com", "email_verified": true, "name": "dewi"}
+	code, resp := h.oidcLogin(issuer, claims)
+	require.Equal(t, 200, code, string(resp.Data))
+
+	var tokens tokenResult
+	resp.decode(t, &tokens)
+	userID, user := h.profile(tokens.AccessToken)
+	assert.Equal(t, "dewi@example.com", user.Email)
+	assert.True(t, user.IsVerified)
+
+	h.createMeal(tokens.AccessToken, "fried rice")
+
+	// The account is found by subject from now on, even if the email changes.
+	claims["email"] = "dewi@other.example.com"
+	code, resp = h.oidcLogin(issuer, claims)
+	require.Equal(t, 200, code, string(resp.Data))
+	resp.decode(t, &tokens)
+	sameID, _ := h.profile(tokens.AccessToken)
+	assert.Equal(t, userID, sameID)
+
+	var identities int64
+	require.NoError(t, h.db.Model(&entity.UserIdentity{}).Count(&identities).Error)
+	assert.EqualValues(t, 1, identities)
+}
+
+func TestOIDCLoginLinksExistingUserByEmail(t *testing.T) {
+	h, issuer := newOIDCHarness(t)
+	userID, _ := h.newUser("alfan")
+
+	code, resp := h.oidcLogin(issuer, jwt.MapClaims{"sub": "user-2", "email": "alfan@example.com", "email_verified": true})
+	require.Equal(t, 200, code, string(resp.Data))
+
+	var tokens tokenResult
+	resp.decode(t, &tokens)
+	linkedID, _ := h.profile(tokens.AccessToken)
+	assert.Equal(t, userID, linkedID)
+
+	// The password keeps working alongside the provider.
+	h.login("alfan@example.com", "Secret123!")
+}
+
+func TestOIDCLoginRefusesUnverifiedAccount(t *testing.T) {
+	h, issuer := newOIDCHarness(t)
+	// Someone else registered the address first and never verified it.
+	userID := h.register("squatter", "alfan@example.com", "Intruder123!")
+
+	claims := jwt.MapClaims{"sub": "user-5", "email": "alfan@example.com", "email_verified": true}
+	code, resp := h.oidcLogin(issuer, claims)
+	assert.Equal(t, 409, code)
+	assert.Equal(t, "an unverified account already uses this email address, reset its password to claim it", resp.errorMessage(t))
+
+	var identities int64
+	require.NoError(t, h.db.Model(&entity.UserIdentity{}).Count(&identities).Error)
+	assert.Zero(t, identities)
+
+	// Resetting the password proves the address, after which it links.
+	h.forgotPassword("alfan@example.com")
+	code, resp = h.request("POST", "/api/password/reset", "", fiber.Map{
+		"token":    h.mailToken("alfan@example.com"),
+		"password": "NewSecret123!",
+	})
+	require.Equal(t, 200, code, string(resp.Data))
+
+	code, resp = h.oidcLogin(issuer, claims)
+	require.Equal(t, 200, code, string(resp.Data))
+	var tokens tokenResult
+	resp.decode(t, &tokens)
+	linkedID, user := h.profile(tokens.AccessToken)
+	assert.Equal(t, userID, linkedID)
+	assert.True(t, user.IsVerified)
+
+	code, _ = h.request("POST", "/api/login", "", fiber.Map{"email": "alfan@example.com", "password": "Intruder123!"})
+	assert.Equal(t, 401, code)
+}
+
+func TestEmailChangeVoidsResetTokenBeforeLinking(t *testing.T) {
+	h, issuer := newOIDCHarness(t)
+	h.register("mallory", "mallory@example.com", "Intruder123!")
+	token := h.login("mallory@example.com", "Intruder123!")
+
+	// A reset mailed to the old address must not verify the new one.
+	h.forgotPassword("mallory@example.com")
+	resetToken := h.mailToken("mallory@example.com")
+	code, _ := h.request("PUT", "/api/users", token, fiber.Map{"email": "alfan@example.com"})
+	require.Equal(t, 200, code)
+
+	code, resp := h.request("POST", "/api/password/reset", "", fiber.Map{
+		"token":    resetToken,
+		"password": "NewSecret123!",
+	})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "invalid or expired password reset token", resp.errorMessage(t))
+
+	code, _ = h.oidcLogin(issuer, jwt.MapClaims{"sub": "user-6", "email": "alfan@example.com", "email_verified": true})
+	assert.Equal(t, 409, code)
+}
+
+func TestOIDCLoginRefusesForcedPasswordReset(t *testing.T) {
+	h, issuer := newOIDCHarness(t)
+	adminToken := h.newAdmin()
+	userID, _ := h.newUser("alfan")
+	claims := jwt.MapClaims{"sub": "user-7", "email": "alfan@example.com", "email_verified": true}
+
+	code, resp := h.oidcLogin(issuer, claims)
+	require.Equal(t, 200, code, string(resp.Data))
+
+	code, resp = h.request("POST", adminUserPath(userID, "/password-reset"), adminToken, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+
+	code, resp = h.oidcLogin(issuer, claims)
+	assert.Equal(t, 403, code)
+	assert.Equal(t, "a password reset is required, check your email", resp.errorMessage(t))
+}
+
+func TestOIDCLoginRequiresVerifiedEmail(t *testing.T) {
+	h, issuer := newOIDCHarness(t)
+	h.newUser("alfan")
+
+	code, resp := h.oidcLogin(issuer, jwt.MapClaims{"sub": "user-3", "email": "alfan@example.com", "email_verified": false})
+	assert.Equal(t, 403, code)
+	assert.Equal(t, "identity provider has not verified the email address", resp.errorMessage(t))
+
+	var identities int64
+	require.NoError(t, h.db.Model(&entity.UserIdentity{}).Count(&identities).Error)
+	assert.Zero(t, identities)
+}
+
+func TestOIDCStateIsSingleUse(t *testing.T) {
+	h, issuer := newOIDCHarness(t)
+	claims := jwt.MapClaims{"sub": "user-4", "email": "sari@example.com", "email_verified": true}
+
+	loginURL := h.oidcLoginURL("mock")
+	code, resp := h.oidcCallback(loginURL, issuer.authorize(t, loginURL, claims))
+	require.Equal(t, 200, code, string(resp.Data))
+
+	code, resp = h.oidcCallback(loginURL, issuer.authorize(t, loginURL, claims))
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "invalid or expired login state, start the login again", resp.errorMessage(t))
+}
+
+func TestOIDCLoginRejectsWrongCode(t *testing.T) {
+	h, _ := newOIDCHarness(t)
+
+	code, resp := h.oidcCallback(h.oidcLoginURL("mock"), "forged-code")
+	assert.Equal(t, 401, code)
+	assert.Equal(t, "identity provider login failed", resp.errorMessage(t))
+}
+
+func TestOIDCUnknownProvider(t *testing.T) {
+	h, _ := newOIDCHarness(t)
+
+	code, resp := h.request("GET", "/api/oidc/unknown/login", "", nil)
+	assert.Equal(t, 404, code)
+	assert.Equal(t, "identity provider not found", resp.errorMessage(t))
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fieldResult struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (r response) fields(t *testing.T) []fieldResult {
+	t.Helper()
+	var data struct {
+		Fields []fieldResult `json:"fields"`
+	}
+	r.decode(t, &data)
+	return data.Fields
+}
+
+func TestChangePasswordRequiresCurrentPassword(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, resp := h.request("PUT", "/api/users/change-password", token, fiber.Map{
+		"password": "NewSecret123!",
+	})
+	require.Equal(t, 422, code)
+	assert.Equal(t, "current_password", resp.fields(t)[0].Field)
+
+	code, resp = h.request("PUT", "/api/users/change-password", token, fiber.Map{
+		"current_password": "Wrong123!",
+		"password":         "NewSecret123!",
+	})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "current password is incorrect", resp.errorMessage(t))
+
+	code, _ = h.request("GET", "/api/users", token, nil)
+	assert.Equal(t, 200, code)
+}
+
+func TestChangePasswordPolicy(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	tests := []struct {
+		password string
+		rule     string
+		message  string
+	}{
+		{"Ab1", "password_length", "password must be at least 8 characters long"},
+		{"lowercase123", "password_classes", "password must contain an uppercase letter, a lowercase letter and a digit"},
+		{"Password1", "password_common", "password is too common, choose one that is harder to guess"},
+		{"Alfan2024x", "password_identity", "password must not contain your username or email"},
+		{"Aa1" + strings.Repeat("é", 40), "password_bytes", "password must be at most 72 bytes long, accented letters and emoji take more than one"},
+	}
+
+	for _, tt := range tests {
+		code, resp := h.request("PUT", "/api/users/change-password", token, fiber.Map{
+			"current_password": "Secret123!",
+			"password":         tt.password,
+		})
+		require.Equal(t, 422, code, tt.password)
+
+		fields := resp.fields(t)
+		require.Len(t, fields, 1)
+		assert.Equal(t, "password", fields[0].Field)
+		assert.Equal(t, tt.rule, fields[0].Rule)
+		assert.Equal(t, tt.message, fields[0].Message)
+	}
+}
+
+func TestRegisterPasswordPolicy(t *testing.T) {
+	h := newHarness(t)
+
+	body := `{"username":"alfan","email":"alfan@example.com","password":"short"}`
+	req := httptest.NewRequest("POST", "/api/register", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderAcceptLanguage, "id")
+
+	code, resp := h.do(req)
+	require.Equal(t, 422, code)
+	assert.Equal(t, "password minimal 8 karakter", resp.errorMessage(t))
+
+	// 43 characters but 83 bytes, more than bcrypt can hash.
+	code, resp = h.request("POST", "/api/register", "", fiber.Map{
+		"username": "alfan",
+		"email":    "alfan@example.com",
+		"password": "Aa1" + strings.Repeat("é", 40),
+	})
+	require.Equal(t, 422, code, string(resp.Data))
+	assert.Equal(t, "password_bytes", resp.fields(t)[0].Rule)
+}
+
+func TestResetPasswordPolicyKeepsToken(t *testing.T) {
+	h := newHarness(t)
+	h.newUser("alfan")
+	h.forgotPassword("alfan@example.com")
+	resetToken := h.mailToken("alfan@example.com")
+
+	code, _ := h.request("POST", "/api/password/reset", "", fiber.Map{
+		"token":    resetToken,
+		"password": "password",
+	})
+	require.Equal(t, 422, code)
+
+	code, _ = h.request("POST", "/api/password/reset", "", fiber.Map{
+		"token":    resetToken,
+		"password": "NewSecret123!",
+	})
+	assert.Equal(t, 200, code)
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (h *harness) forgotPassword(email string) {
+	h.t.Helper()
+
+	code, resp := h.request("POST", "/api/password/forgot", "", fiber.Map{"email": email})
+	require.Equal(h.t, 200, code, string(resp.Data))
+}
+
+func TestResetPassword(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	h.forgotPassword("alfan@example.com")
+	resetToken := h.mailToken("alfan@example.com")
+
+	code, _ := h.request("POST", "/api/password/reset", "", fiber.Map{
+		"token":    resetToken,
+		"password": "NewSecret123!",
+	})
+	require.Equal(t, 200, code)
+
+	code, _ = h.request("GET", "/api/users", token, nil)
+	assert.Equal(t, 401, code)
+
+	code, _ = h.request("POST", "/api/login", "", fiber.Map{"email": "alfan@example.com", "password": "Secret123!"})
+	assert.Equal(t, 401, code)
+	h.login("alfan@example.com", "NewSecret123!")
+
+	code, resp := h.request("POST", "/api/password/reset", "", fiber.Map{
+		"token":    resetToken,
+		"password": "Another123!",
+	})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "invalid or expired password reset token", resp.errorMessage(t))
+}
+
+func TestResetPasswordOnlyLatestTokenWorks(t *testing.T) {
+	h := newHarness(t)
+	h.newUser("alfan")
+
+	h.forgotPassword("alfan@example.com")
+	first := h.mailToken("alfan@example.com")
+	h.forgotPassword("alfan@example.com")
+
+	code, _ := h.request("POST", "/api/password/reset", "", fiber.Map{
+		"token":    first,
+		"password": "NewSecret123!",
+	})
+	assert.Equal(t, 400, code)
+}
+
+func TestForgotPasswordUnknownEmail(t *testing.T) {
+	h := newHarness(t)
+
+	h.forgotPassword("nobody@example.com")
+	assert.Empty(t, h.mails("nobody@example.com"))
+}
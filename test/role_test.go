@@ -0,0 +1,91 @@
+package test
+
+import (
+	"meals-app/model/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModeratorCanEditButNotDeleteMeals(t *testing.T) {
+	h := newHarness(t)
+	_, ownerToken := h.newUser("alfan")
+	moderatorID, moderatorToken := h.newUser("moderator")
+	h.setRole(moderatorID, entity.RoleModerator)
+	_, otherToken := h.newUser("sari")
+	meal := h.createMeal(ownerToken, "fried rice")
+
+	code, _ := h.multipart("PUT", mealPath(meal.ID, ""), otherToken, map[string][]string{"name": {"spam"}}, "")
+	assert.Equal(t, 403, code)
+
+	code, resp := h.multipart("PUT", mealPath(meal.ID, ""), moderatorToken, map[string][]string{"name": {"nasi goreng"}}, "")
+	require.Equal(t, 200, code, string(resp.Data))
+	var updated mealResult
+	resp.decode(t, &updated)
+	assert.Equal(t, "nasi goreng", updated.Name)
+
+	code, _ = h.multipart("PUT", mealPath(meal.ID, "/image"), moderatorToken, nil, "new.jpg")
+	assert.Equal(t, 200, code)
+
+	code, _ = h.request("DELETE", mealPath(meal.ID, ""), moderatorToken, nil)
+	assert.Equal(t, 403, code)
+
+	code, _ = h.request("POST", "/api/admin/users/1/unlock", moderatorToken, nil)
+	assert.Equal(t, 403, code)
+}
+
+func TestHideMeal(t *testing.T) {
+	h := newHarness(t)
+	_, ownerToken := h.newUser("alfan")
+	moderatorID, moderatorToken := h.newUser("moderator")
+	h.setRole(moderatorID, entity.RoleModerator)
+	_, otherToken := h.newUser("sari")
+	meal := h.createMeal(ownerToken, "fried rice")
+
+	code, _ := h.request("POST", mealPath(meal.ID, "/hide"), otherToken, nil)
+	assert.Equal(t, 403, code)
+
+	code, resp := h.request("POST", mealPath(meal.ID, "/hide"), moderatorToken, nil)
+	require.Equal(t, 200, code, string(resp.Data))
+	assert.Contains(t, string(resp.Data), `"is_hidden":true`)
+
+	code, resp = h.request("GET", "/api/meals/", otherToken, nil)
+	require.Equal(t, 200, code)
+	var meals []mealResult
+	resp.decode(t, &meals)
+	assert.Empty(t, meals)
+
+	code, _ = h.request("GET", mealPath(meal.ID, ""), otherToken, nil)
+	assert.Equal(t, 404, code)
+	code, _ = h.request("POST", mealPath(meal.ID, "/favorites"), otherToken, nil)
+	assert.Equal(t, 404, code)
+
+	for _, token := range []string{ownerToken, moderatorToken} {
+		code, _ = h.request("GET", mealPath(meal.ID, ""), token, nil)
+		assert.Equal(t, 200, code)
+	}
+
+	code, _ = h.request("DELETE", mealPath(meal.ID, "/hide"), moderatorToken, nil)
+	require.Equal(t, 200, code)
+
+	code, _ = h.request("GET", mealPath(meal.ID, ""), otherToken, nil)
+	assert.Equal(t, 200, code)
+}
+
+func TestProfileListsPermissions(t *testing.T) {
+	h := newHarness(t)
+	moderatorID, token := h.newUser("moderator")
+	h.setRole(moderatorID, entity.RoleModerator)
+
+	code, resp := h.request("GET", "/api/users/", token, nil)
+	require.Equal(t, 200, code)
+
+	var profile struct {
+		Role        string   `json:"role"`
+		Permissions []string `json:"permissions"`
+	}
+	resp.decode(t, &profile)
+	assert.Equal(t, entity.RoleModerator, profile.Role)
+	assert.ElementsMatch(t, []string{entity.PermissionUpdateAnyMeal, entity.PermissionHideMeal}, profile.Permissions)
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sessionResult struct {
+	ID        string `json:"id"`
+	UserAgent string `json:"user_agent"`
+	Current   bool   `json:"current"`
+}
+
+func (h *harness) sessions(token string) []sessionResult {
+	h.t.Helper()
+
+	code, resp := h.request("GET", "/api/users/sessions", token, nil)
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var sessions []sessionResult
+	resp.decode(h.t, &sessions)
+	return sessions
+}
+
+func TestLogoutRevokesCurrentSession(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	tokens := h.loginTokens("alfan@example.com", "Secret123!")
+	other := h.login("alfan@example.com", "Secret123!")
+
+	code, _ := h.request("POST", "/api/logout", tokens.AccessToken, nil)
+	require.Equal(t, 200, code)
+
+	code, resp := h.request("GET", "/api/users", tokens.AccessToken, nil)
+	assert.Equal(t, 401, code)
+	assert.Equal(t, "session revoked", resp.errorMessage(t))
+
+	code, _, _ = h.refresh(tokens.RefreshToken)
+	assert.Equal(t, 401, code)
+
+	code, _ = h.request("GET", "/api/users", other, nil)
+	assert.Equal(t, 200, code)
+}
+
+func TestListSessions(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	first := h.login("alfan@example.com", "Secret123!")
+	h.login("alfan@example.com", "Secret123!")
+
+	sessions := h.sessions(first)
+	require.Len(t, sessions, 2)
+
+	current := 0
+	for _, session := range sessions {
+		if session.Current {
+			current++
+		}
+	}
+	assert.Equal(t, 1, current)
+}
+
+func TestDeleteSession(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	first := h.login("alfan@example.com", "Secret123!")
+	second := h.login("alfan@example.com", "Secret123!")
+
+	var target string
+	for _, session := range h.sessions(first) {
+		if !session.Current {
+			target = session.ID
+		}
+	}
+	require.NotEmpty(t, target)
+
+	code, _ := h.request("DELETE", "/api/users/sessions/"+target, first, nil)
+	require.Equal(t, 200, code)
+
+	code, _ = h.request("GET", "/api/users", second, nil)
+	assert.Equal(t, 401, code)
+	assert.Len(t, h.sessions(first), 1)
+}
+
+func TestDeleteSessionOfAnotherUser(t *testing.T) {
+	h := newHarness(t)
+	_, ownerToken := h.newUser("owner")
+	_, otherToken := h.newUser("other")
+
+	sessionID := h.sessions(ownerToken)[0].ID
+
+	code, _ := h.request("DELETE", "/api/users/sessions/"+sessionID, otherToken, nil)
+	assert.Equal(t, 404, code)
+
+	code, _ = h.request("GET", "/api/users", ownerToken, nil)
+	assert.Equal(t, 200, code)
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"meals-app/mail"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts one connection and answers it with handle.
+func fakeSMTPServer(t *testing.T, handle func(conn net.Conn)) (string, int) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handle(conn)
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestSMTPMailerDelivers(t *testing.T) {
+	received := make(chan string, 1)
+	host, port := fakeSMTPServer(t, func(conn net.Conn) {
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 fake ready")
+		var data strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.Fields(line)[0]); command {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				reply("250 ok")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 unknown")
+			}
+		}
+	})
+
+	mailer := mail.NewSMTPMailer(host, port, "", "", "Meals App <no-reply@example.com>", 5*time.Second)
+	err := mailer.Send(context.Background(), mail.Message{To: "alfan@example.com", Subject: "Hello", Body: "Hi there"})
+	require.NoError(t, err)
+
+	message := <-received
+	assert.Contains(t, message, "Subject: Hello\r\n")
+	assert.Contains(t, message, "Hi there")
+}
+
+func TestSMTPMailerGivesUpOnSilentServer(t *testing.T) {
+	host, port := fakeSMTPServer(t, func(conn net.Conn) {
+		// Never greet, like a server that hangs.
+		time.Sleep(5 * time.Second)
+	})
+
+	mailer := mail.NewSMTPMailer(host, port, "", "", "no-reply@example.com", 200*time.Millisecond)
+	start := time.Now()
+	err := mailer.Send(context.Background(), mail.Message{To: "alfan@example.com", Subject: "Hello", Body: "Hi"})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	// A context that ends first wins over the timeout.
+	host, port = fakeSMTPServer(t, func(conn net.Conn) { time.Sleep(5 * time.Second) })
+	mailer = mail.NewSMTPMailer(host, port, "", "", "no-reply@example.com", time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	err = mailer.Send(ctx, mail.Message{To: "alfan@example.com", Subject: "Hello", Body: "Hi"})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (h *harness) refresh(refreshToken string) (int, tokenResult, response) {
+	h.t.Helper()
+
+	code, resp := h.request("POST", "/api/token/refresh", "", fiber.Map{
+		"refresh_token": refreshToken,
+	})
+
+	var tokens tokenResult
+	if code == 200 {
+		resp.decode(h.t, &tokens)
+	}
+	return code, tokens, resp
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	tokens := h.loginTokens("alfan@example.com", "Secret123!")
+	require.NotEmpty(t, tokens.RefreshToken)
+
+	code, rotated, resp := h.refresh(tokens.RefreshToken)
+	require.Equal(t, 200, code, string(resp.Data))
+	assert.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken)
+
+	code, _ = h.request("GET", "/api/users", rotated.AccessToken, nil)
+	assert.Equal(t, 200, code)
+
+	code, _, _ = h.refresh(rotated.RefreshToken)
+	assert.Equal(t, 200, code)
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	tokens := h.loginTokens("alfan@example.com", "Secret123!")
+
+	code, rotated, _ := h.refresh(tokens.RefreshToken)
+	require.Equal(t, 200, code)
+
+	code, _, resp := h.refresh(tokens.RefreshToken)
+	assert.Equal(t, 401, code)
+	assert.Equal(t, "refresh token reuse detected, login instead", resp.errorMessage(t))
+
+	code, _, _ = h.refresh(rotated.RefreshToken)
+	assert.Equal(t, 401, code)
+}
+
+func TestRefreshTokenFamiliesAreIndependent(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	first := h.loginTokens("alfan@example.com", "Secret123!")
+	second := h.loginTokens("alfan@example.com", "Secret123!")
+
+	h.refresh(first.RefreshToken)
+	h.refresh(first.RefreshToken)
+
+	code, _, _ := h.refresh(second.RefreshToken)
+	assert.Equal(t, 200, code)
+}
+
+func TestRefreshTokenRejectedAfterPasswordChange(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	tokens := h.loginTokens("alfan@example.com", "Secret123!")
+
+	code, _ := h.request("PUT", "/api/users/change-password", tokens.AccessToken, fiber.Map{
+		"current_password": "Secret123!",
+		"password":         "NewSecret123!",
+	})
+	require.Equal(t, 200, code)
+
+	code, _, _ = h.refresh(tokens.RefreshToken)
+	assert.Equal(t, 401, code)
+}
+
+func TestRefreshWithUnknownToken(t *testing.T) {
+	h := newHarness(t)
+
+	code, _, resp := h.refresh("unknown")
+	assert.Equal(t, 401, code)
+	assert.Equal(t, "invalid refresh token", resp.errorMessage(t))
+}
@@ -0,0 +1,223 @@
+package test
+
+import (
+	"meals-app/totp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type enrollResult struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type recoveryCodesResult struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type challengeResult struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	AccessToken       string `json:"access_token"`
+}
+
+// totpCode returns the code for the current time step plus offset. Each
+// code is accepted once, so a test moves the offset forward for every code
+// it submits.
+func totpCode(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+
+	code, err := totp.Code(secret, totp.Step(time.Now())+offset)
+	require.NoError(t, err)
+	return code
+}
+
+// enableTwoFactor enrolls a user created by newUser and confirms with the
+// current code, returning the secret and the recovery codes.
+func (h *harness) enableTwoFactor(token string) (string, []string) {
+	h.t.Helper()
+
+	code, resp := h.request("POST", "/api/users/2fa/enroll", token, fiber.Map{"current_password": "Secret123!"})
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var enrolled enrollResult
+	resp.decode(h.t, &enrolled)
+
+	code, resp = h.request("POST", "/api/users/2fa/confirm", token, fiber.Map{
+		"current_password": "Secret123!",
+		"code":             totpCode(h.t, enrolled.Secret, 0),
+	})
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var recovery recoveryCodesResult
+	resp.decode(h.t, &recovery)
+	return enrolled.Secret, recovery.RecoveryCodes
+}
+
+func (h *harness) loginChallenge(email string, password string) string {
+	h.t.Helper()
+
+	code, resp := h.request("POST", "/api/login", "", fiber.Map{"email": email, "password": password})
+	require.Equal(h.t, 200, code, string(resp.Data))
+
+	var challenge challengeResult
+	resp.decode(h.t, &challenge)
+	require.True(h.t, challenge.TwoFactorRequired)
+	require.Empty(h.t, challenge.AccessToken)
+	return challenge.ChallengeToken
+}
+
+func (h *harness) verifyTwoFactor(challenge string, code string) (int, response) {
+	h.t.Helper()
+	return h.request("POST", "/api/login/2fa", "", fiber.Map{"challenge_token": challenge, "code": code})
+}
+
+func TestTOTPMatchesRFC6238(t *testing.T) {
+	// RFC 6238 appendix B, SHA-1 secret "12345678901234567890" at T = 59s,
+	// truncated to six digits.
+	code, err := totp.Code("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", totp.Step(time.Unix(59, 0)))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+}
+
+func TestTwoFactorEnrollAndLogin(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	// A stolen access token alone is not enough to turn it on.
+	code, resp := h.request("POST", "/api/users/2fa/enroll", token, fiber.Map{})
+	assert.Equal(t, 422, code)
+	code, resp = h.request("POST", "/api/users/2fa/enroll", token, fiber.Map{"current_password": "Wrong123!"})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "current password is incorrect", resp.errorMessage(t))
+
+	code, resp = h.request("POST", "/api/users/2fa/enroll", token, fiber.Map{"current_password": "Secret123!"})
+	require.Equal(t, 200, code, string(resp.Data))
+	var enrolled enrollResult
+	resp.decode(t, &enrolled)
+	assert.True(t, strings.HasPrefix(enrolled.OTPAuthURI, "otpauth://totp/"))
+	assert.Contains(t, enrolled.OTPAuthURI, "secret="+enrolled.Secret)
+
+	// Logging in still needs only the password until enrollment is confirmed.
+	h.login("alfan@example.com", "Secret123!")
+
+	code, resp = h.request("POST", "/api/users/2fa/confirm", token, fiber.Map{"current_password": "Secret123!", "code": "000000"})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "invalid two-factor code", resp.errorMessage(t))
+
+	currentCode := totpCode(t, enrolled.Secret, 0)
+	code, resp = h.request("POST", "/api/users/2fa/confirm", token, fiber.Map{"current_password": "Wrong123!", "code": currentCode})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "current password is incorrect", resp.errorMessage(t))
+
+	code, resp = h.request("POST", "/api/users/2fa/confirm", token, fiber.Map{"current_password": "Secret123!", "code": currentCode})
+	require.Equal(t, 200, code, string(resp.Data))
+	var recovery recoveryCodesResult
+	resp.decode(t, &recovery)
+	assert.Len(t, recovery.RecoveryCodes, 10)
+
+	code, resp = h.request("GET", "/api/users/", token, nil)
+	require.Equal(t, 200, code)
+	assert.Contains(t, string(resp.Data), `"two_factor_enabled":true`)
+
+	challenge := h.loginChallenge("alfan@example.com", "Secret123!")
+
+	code, resp = h.verifyTwoFactor(challenge, "000000")
+	assert.Equal(t, 401, code)
+	assert.Equal(t, "invalid two-factor code", resp.errorMessage(t))
+
+	code, resp = h.verifyTwoFactor(challenge, totpCode(t, enrolled.Secret, 1))
+	require.Equal(t, 200, code, string(resp.Data))
+	var tokens tokenResult
+	resp.decode(t, &tokens)
+	code, _ = h.request("GET", "/api/users/", tokens.AccessToken, nil)
+	assert.Equal(t, 200, code)
+
+	code, resp = h.verifyTwoFactor(challenge, recovery.RecoveryCodes[0])
+	assert.Equal(t, 401, code)
+	assert.Equal(t, "invalid or expired login challenge, login again", resp.errorMessage(t))
+}
+
+func TestTwoFactorCodeCannotBeReplayed(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	secret, _ := h.enableTwoFactor(token)
+
+	// The confirmation already used the current code.
+	code, _ := h.verifyTwoFactor(h.loginChallenge("alfan@example.com", "Secret123!"), totpCode(t, secret, 0))
+	assert.Equal(t, 401, code)
+}
+
+func TestTwoFactorRecoveryCodeIsSingleUse(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	_, recoveryCodes := h.enableTwoFactor(token)
+
+	code, resp := h.verifyTwoFactor(h.loginChallenge("alfan@example.com", "Secret123!"), recoveryCodes[0])
+	require.Equal(t, 200, code, string(resp.Data))
+
+	challenge := h.loginChallenge("alfan@example.com", "Secret123!")
+	code, _ = h.verifyTwoFactor(challenge, recoveryCodes[0])
+	assert.Equal(t, 401, code)
+
+	// Recovery codes are accepted regardless of case and dashes.
+	code, resp = h.verifyTwoFactor(challenge, strings.ToUpper(strings.ReplaceAll(recoveryCodes[1], "-", "")))
+	assert.Equal(t, 200, code, string(resp.Data))
+}
+
+func TestTwoFactorWrongCodesLockOut(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	secret, _ := h.enableTwoFactor(token)
+
+	challenge := h.loginChallenge("alfan@example.com", "Secret123!")
+	for i := 0; i < h.cfg.Login.MaxAttempts; i++ {
+		code, _ := h.verifyTwoFactor(challenge, "000000")
+		require.Equal(t, 401, code)
+	}
+
+	code, _ := h.verifyTwoFactor(challenge, totpCode(t, secret, 1))
+	assert.Equal(t, 429, code)
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	secret, oldCodes := h.enableTwoFactor(token)
+
+	code, resp := h.request("POST", "/api/users/2fa/recovery-codes", token, fiber.Map{"code": totpCode(t, secret, 1)})
+	require.Equal(t, 200, code, string(resp.Data))
+	var recovery recoveryCodesResult
+	resp.decode(t, &recovery)
+	require.Len(t, recovery.RecoveryCodes, 10)
+
+	challenge := h.loginChallenge("alfan@example.com", "Secret123!")
+	code, _ = h.verifyTwoFactor(challenge, oldCodes[0])
+	assert.Equal(t, 401, code)
+	code, _ = h.verifyTwoFactor(challenge, recovery.RecoveryCodes[0])
+	assert.Equal(t, 200, code)
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	_, recoveryCodes := h.enableTwoFactor(token)
+
+	code, resp := h.request("DELETE", "/api/users/2fa", token, fiber.Map{"password": "Wrong123!", "code": recoveryCodes[0]})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "current password is incorrect", resp.errorMessage(t))
+
+	code, resp = h.request("DELETE", "/api/users/2fa", token, fiber.Map{"password": "Secret123!", "code": recoveryCodes[0]})
+	require.Equal(t, 200, code, string(resp.Data))
+
+	assert.NotEmpty(t, h.login("alfan@example.com", "Secret123!"))
+
+	code, resp = h.request("POST", "/api/users/2fa/recovery-codes", token, fiber.Map{"code": "000000"})
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "two-factor authentication is not enabled", resp.errorMessage(t))
+}
@@ -0,0 +1,177 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterSuccess(t *testing.T) {
+	h := newHarness(t)
+
+	code, resp := h.request("POST", "/api/register", "", fiber.Map{
+		"username": "alfan",
+		"email":    "alfan@example.com",
+		"password": "Secret123!",
+	})
+	require.Equal(t, 200, code)
+
+	var user struct {
+		Username string `json:"username"`
+		Email    string `json:"email"`
+		Role     string `json:"role"`
+	}
+	resp.decode(t, &user)
+	assert.Equal(t, "alfan", user.Username)
+	assert.Equal(t, "alfan@example.com", user.Email)
+	assert.Equal(t, "user", user.Role)
+}
+
+func TestRegisterDuplicateEmail(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+
+	code, resp := h.request("POST", "/api/register", "", fiber.Map{
+		"username": "other",
+		"email":    "alfan@example.com",
+		"password": "Secret123!",
+	})
+	assert.Equal(t, 409, code)
+	assert.Equal(t, "email already exists", resp.errorMessage(t))
+}
+
+func TestRegisterValidationError(t *testing.T) {
+	h := newHarness(t)
+
+	code, resp := h.request("POST", "/api/register", "", fiber.Map{
+		"username": "alfan",
+		"email":    "not-an-email",
+	})
+	require.Equal(t, 422, code)
+
+	var data struct {
+		Fields []struct {
+			Field string `json:"field"`
+			Rule  string `json:"rule"`
+		} `json:"fields"`
+	}
+	resp.decode(t, &data)
+	require.Len(t, data.Fields, 2)
+	assert.Equal(t, "email", data.Fields[0].Field)
+	assert.Equal(t, "email", data.Fields[0].Rule)
+	assert.Equal(t, "password", data.Fields[1].Field)
+	assert.Equal(t, "required", data.Fields[1].Rule)
+}
+
+func TestLoginWrongPassword(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+
+	code, resp := h.request("POST", "/api/login", "", fiber.Map{
+		"email":    "alfan@example.com",
+		"password": "wrong",
+	})
+	assert.Equal(t, 401, code)
+	assert.Equal(t, "INVALID CREDENTIALS", resp.Status)
+}
+
+func TestProfile(t *testing.T) {
+	h := newHarness(t)
+	userID, token := h.newUser("alfan")
+
+	code, resp := h.request("GET", "/api/users", token, nil)
+	require.Equal(t, 200, code)
+
+	var user struct {
+		ID int `json:"id"`
+	}
+	resp.decode(t, &user)
+	assert.Equal(t, userID, user.ID)
+}
+
+func TestProfileWithoutToken(t *testing.T) {
+	h := newHarness(t)
+
+	code, resp := h.request("GET", "/api/users", "", nil)
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "missing or malformed JWT", resp.errorMessage(t))
+}
+
+func TestProfileWithInvalidToken(t *testing.T) {
+	h := newHarness(t)
+
+	code, _ := h.request("GET", "/api/users", "eyJhbGciOiJIUzI1NiJ9.e30.invalid", nil)
+	assert.Equal(t, 401, code)
+}
+
+func TestPasswordChangeRevokesExistingTokens(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, _ := h.request("PUT", "/api/users/change-password", token, fiber.Map{
+		"current_password": "Secret123!",
+		"password":         "NewSecret123!",
+	})
+	require.Equal(t, 200, code)
+
+	code, resp := h.request("GET", "/api/users", token, nil)
+	assert.Equal(t, 401, code)
+	assert.Equal(t, "token revoked", resp.errorMessage(t))
+
+	newToken := h.login("alfan@example.com", "NewSecret123!")
+	code, _ = h.request("GET", "/api/users", newToken, nil)
+	assert.Equal(t, 200, code)
+}
+
+func TestEmailChangeRevokesExistingTokens(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, _ := h.request("PUT", "/api/users", token, fiber.Map{
+		"email": "new@example.com",
+	})
+	require.Equal(t, 200, code)
+
+	code, _ = h.request("GET", "/api/users", token, nil)
+	assert.Equal(t, 401, code)
+}
+
+func TestUpdateProfileDuplicateEmail(t *testing.T) {
+	h := newHarness(t)
+	h.newUser("first")
+	_, token := h.newUser("second")
+
+	code, _ := h.request("PUT", "/api/users", token, fiber.Map{
+		"email": "first@example.com",
+	})
+	assert.Equal(t, 409, code)
+}
+
+func TestUpdateProfileImage(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+
+	code, resp := h.multipart("PUT", "/api/users/image", token, nil, "avatar.png")
+	require.Equal(t, 200, code)
+
+	var user struct {
+		Image string `json:"user_image_url"`
+	}
+	resp.decode(t, &user)
+	assert.Equal(t, "https://images.test/avatar.png", user.Image)
+	assert.Contains(t, h.imageStore.uploads, "avatar.png")
+}
+
+func TestUnexpectedErrorsAreNotShownToClients(t *testing.T) {
+	h := newHarness(t)
+	_, token := h.newUser("alfan")
+	h.imageStore.uploadErr = errors.New("cloudinary: api_secret mismatch for key 1234")
+
+	code, resp := h.multipart("PUT", "/api/users/image", token, nil, "avatar.png")
+	assert.Equal(t, 500, code)
+	assert.Equal(t, "internal server error", resp.errorMessage(t))
+	assert.NotContains(t, string(resp.Data), "cloudinary")
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type verifiedResult struct {
+	Email      string `json:"email"`
+	IsVerified bool   `json:"is_verified"`
+}
+
+func TestRegisterRequiresVerificationToCreateMeals(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	token := h.login("alfan@example.com", "Secret123!")
+
+	require.Len(t, h.mails("alfan@example.com"), 1)
+
+	code, resp := h.multipart("POST", "/api/meals", token, mealFields("fried rice"), "fried-rice.jpg")
+	assert.Equal(t, 403, code)
+	assert.Equal(t, "verify your email address first", resp.errorMessage(t))
+
+	code, resp = h.request("GET", "/api/verify-email?token="+url.QueryEscape(h.mailToken("alfan@example.com")), "", nil)
+	require.Equal(t, 200, code)
+
+	var user verifiedResult
+	resp.decode(t, &user)
+	assert.True(t, user.IsVerified)
+
+	h.createMeal(token, "fried rice")
+}
+
+func TestVerifyEmailTokenIsSingleUse(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	path := "/api/verify-email?token=" + url.QueryEscape(h.mailToken("alfan@example.com"))
+
+	code, _ := h.request("GET", path, "", nil)
+	require.Equal(t, 200, code)
+
+	code, resp := h.request("GET", path, "", nil)
+	assert.Equal(t, 400, code)
+	assert.Equal(t, "invalid or expired verification token", resp.errorMessage(t))
+
+	code, _ = h.request("GET", "/api/verify-email?token=unknown", "", nil)
+	assert.Equal(t, 400, code)
+}
+
+func TestResendVerification(t *testing.T) {
+	h := newHarness(t)
+	h.register("alfan", "alfan@example.com", "Secret123!")
+	token := h.login("alfan@example.com", "Secret123!")
+	first := h.mailToken("alfan@example.com")
+
+	code, _ := h.request("POST", "/api/users/verify-email", token, nil)
+	require.Equal(t, 200, code)
+	require.Len(t, h.mails("alfan@example.com"), 2)
+
+	code, _ = h.request("GET", "/api/verify-email?token="+url.QueryEscape(first), "", nil)
+	assert.Equal(t, 400, code)
+
+	h.verifyEmail("alfan@example.com")
+
+	code, _ = h.request("POST", "/api/users/verify-email", token, nil)
+	assert.Equal(t, 409, code)
+}
+
+func TestChangingEmailRequiresVerification(t *testing.T) {
+	h := newHarness(t)
+	h.newUser("alfan")
+
+	token := h.login("alfan@example.com", "Secret123!")
+	code, resp := h.request("PUT", "/api/users", token, fiber.Map{"email": "new@example.com"})
+	require.Equal(t, 200, code, string(resp.Data))
+
+	var user verifiedResult
+	resp.decode(t, &user)
+	assert.Equal(t, "new@example.com", user.Email)
+	assert.False(t, user.IsVerified)
+	assert.Len(t, h.mails("new@example.com"), 1)
+}